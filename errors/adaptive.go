@@ -0,0 +1,78 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"sort"
+	"sync"
+)
+
+// reorderInterval is the number of matches after which an AdaptiveMatcher
+// reorders its targets.
+const reorderInterval = 256
+
+// An AdaptiveMatcher matches errors against a list of targets, like IsOneOf,
+// but tries the targets that matched most often first, to reduce the number
+// of comparisons in programs that classify many errors against a long list
+// of targets of which a few match most of the time. It counts the matches of
+// each target and, every 256 matches, reorders the targets by decreasing
+// count, keeping the order of targets with the same count.
+//
+// As the order of the targets changes, the targets should be exclusive: if
+// an error matches several targets, which one Match reports may change over
+// time. An AdaptiveMatcher is safe for concurrent use.
+type AdaptiveMatcher struct {
+	mu sync.Mutex
+	// targets is replaced, never modified, when the targets are reordered,
+	// so that it can be used without holding mu.
+	targets []*adaptiveTarget
+	matches int
+}
+
+type adaptiveTarget struct {
+	err  error
+	hits uint64
+}
+
+// NewAdaptiveMatcher returns an AdaptiveMatcher for targets, which are tried
+// in the given order until the first reordering.
+func NewAdaptiveMatcher(targets ...error) *AdaptiveMatcher {
+	m := &AdaptiveMatcher{targets: make([]*adaptiveTarget, len(targets))}
+	for i, t := range targets {
+		m.targets[i] = &adaptiveTarget{err: t}
+	}
+	return m
+}
+
+// Match returns the first target, in the current order of m, that err
+// matches, as defined by Is, and reports whether there is one.
+func (m *AdaptiveMatcher) Match(err error) (target error, ok bool) {
+	m.mu.Lock()
+	targets := m.targets
+	m.mu.Unlock()
+	for _, t := range targets {
+		if isChain(err, t.err) {
+			m.hit(t)
+			return t.err, true
+		}
+	}
+	return nil, false
+}
+
+// hit counts a match of t, reordering the targets if it is time to.
+func (m *AdaptiveMatcher) hit(t *adaptiveTarget) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t.hits++
+	m.matches++
+	if m.matches%reorderInterval != 0 {
+		return
+	}
+	targets := append([]*adaptiveTarget(nil), m.targets...)
+	sort.SliceStable(targets, func(i, j int) bool {
+		return targets[i].hits > targets[j].hits
+	})
+	m.targets = targets
+}
@@ -0,0 +1,68 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors_test
+
+import (
+	"io"
+	"sync"
+	"testing"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/fmt"
+)
+
+// countingTarget is a target that matches nothing and counts the errors it
+// is compared with.
+type countingTarget struct{ n *int }
+
+func (t countingTarget) Error() string { return "countingTarget" }
+
+func (t countingTarget) MatchError(err error) bool {
+	*t.n++
+	return false
+}
+
+func TestAdaptiveMatcher(t *testing.T) {
+	n := 0
+	m := errors.NewAdaptiveMatcher(countingTarget{&n}, io.ErrUnexpectedEOF, io.EOF)
+	err := fmt.Errorf("read: %v", io.EOF)
+	for i := 0; i < 256; i++ {
+		if target, ok := m.Match(err); target != io.EOF || !ok {
+			t.Fatalf("Match(%v) = %v, %v; want %v, true", err, target, ok, io.EOF)
+		}
+	}
+	if n == 0 {
+		t.Fatalf("targets tried before reordering: countingTarget not tried")
+	}
+	n = 0
+	m.Match(err)
+	if n != 0 {
+		t.Errorf("after reordering: countingTarget compared with %d errors, want 0", n)
+	}
+	if target, ok := m.Match(fmt.Errorf("read: %v", io.ErrUnexpectedEOF)); target != io.ErrUnexpectedEOF || !ok {
+		t.Errorf("Match(ErrUnexpectedEOF) = %v, %v; want %v, true", target, ok, io.ErrUnexpectedEOF)
+	}
+	if target, ok := m.Match(errors.New("other")); target != nil || ok {
+		t.Errorf("Match(other) = %v, %v; want nil, false", target, ok)
+	}
+}
+
+func TestAdaptiveMatcherConcurrent(t *testing.T) {
+	m := errors.NewAdaptiveMatcher(io.ErrUnexpectedEOF, io.EOF)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 300; j++ {
+				if _, ok := m.Match(io.EOF); !ok {
+					t.Errorf("Match(EOF) = false, want true")
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
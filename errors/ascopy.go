@@ -0,0 +1,29 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import "reflect"
+
+// AsCopy is like As for a target of type *T, but returns a copy of the error
+// it finds, so that the caller cannot modify the error in err's chain. If T
+// is a pointer type, the copy points to a new value assigned from the value
+// the error points to.
+//
+// The copy is shallow: the values that the copied value refers to, such as
+// the elements of slices and maps, or values behind other pointers, are
+// shared with the error in the chain.
+func AsCopy[T error](err error) (T, bool) {
+	var target T
+	if !As(err, &target) {
+		return target, false
+	}
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return target, true
+	}
+	c := reflect.New(v.Type().Elem())
+	c.Elem().Set(v.Elem())
+	return c.Interface().(T), true
+}
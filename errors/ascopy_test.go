@@ -0,0 +1,45 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors_test
+
+import (
+	"testing"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/fmt"
+)
+
+type fieldsError struct {
+	msg    string
+	fields []string
+}
+
+func (e *fieldsError) Error() string { return e.msg }
+
+func TestAsCopy(t *testing.T) {
+	orig := &fieldsError{"invalid", []string{"a"}}
+	err := fmt.Errorf("wrap: %v", orig)
+
+	c, ok := errors.AsCopy[*fieldsError](err)
+	if !ok || c == orig || c.msg != "invalid" {
+		t.Fatalf("AsCopy = %v, %v; want copy of orig, true", c, ok)
+	}
+	c.fields[0] = "shared"
+	if orig.fields[0] != "shared" {
+		t.Errorf("AsCopy: slice elements are not shared")
+	}
+	c.msg = "changed"
+	c.fields = append(c.fields, "b")
+	if orig.msg != "invalid" || len(orig.fields) != 1 {
+		t.Errorf("AsCopy: modifying the copy changed the original: %+v", *orig)
+	}
+
+	if e, ok := errors.AsCopy[errorT](fmt.Errorf("wrap: %v", errorT{})); !ok || e != (errorT{}) {
+		t.Errorf("AsCopy[errorT] = %v, %v; want errorT{}, true", e, ok)
+	}
+	if c, ok := errors.AsCopy[*fieldsError](errors.New("x")); ok || c != nil {
+		t.Errorf("AsCopy on a chain without match = %v, %v; want nil, false", c, ok)
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Cached returns an error that formats as err, but computes its text at most
+// once: the result of Error, which is also printed by %v and %s, is computed
+// on first use and reused thereafter. The detailed text printed by %+v is
+// not cached, so that it is the same as for err wherever the returned error
+// appears in a chain. The returned error unwraps to err, so Is and As are
+// unaffected. Cached returns nil if err is nil.
+//
+// Cached is only safe for errors whose chain is immutable, so that their
+// text cannot change after it has been computed. This holds for the errors
+// created by this package and by package errors/fmt.
+func Cached(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &cached{err: err}
+}
+
+type cached struct {
+	err error
+
+	once sync.Once
+	text string
+}
+
+func (e *cached) Error() string {
+	e.once.Do(func() { e.text = Format(e.err, false) })
+	return e.text
+}
+
+// Format prints the cached text of e or, if detail is requested, formats
+// err in its place.
+func (e *cached) Format(p Printer) (next error) {
+	if detailRequested(p) {
+		return formatInPlace(p, e.err)
+	}
+	p.Print(e.Error())
+	return nil
+}
+
+func (e *cached) Unwrap() error { return e.err }
+
+func (e *cached) CloneWrapping(errs []error) error {
+	return &cached{err: errs[0]}
+}
+
+// A MatchCache remembers the chains found not to match a target with Is, to
+// speed up the common check that an error is usually not a given sentinel
+// error in programs that test many distinct chains against the same targets.
+// Results are keyed by the identity of the error at the head of the chain,
+// and at most size of them are kept, the least recently used being dropped
+// first. Matches are not cached.
+//
+// A MatchCache is only valid for immutable chains, such as those made of the
+// errors of this package and of package errors/fmt: if an error of a cached
+// chain could change which errors it wraps or how it matches, the cache could
+// report a stale result. A MatchCache is safe for concurrent use.
+type MatchCache struct {
+	mu     sync.Mutex
+	size   int
+	misses map[matchKey]*list.Element
+	lru    list.List // of matchKey, most recently used first
+
+	hits, lookups uint64
+}
+
+type matchKey struct {
+	err, target error
+}
+
+// NewMatchCache returns a MatchCache keeping at most size results. A size of
+// 0 or less disables caching.
+func NewMatchCache(size int) *MatchCache {
+	return &MatchCache{size: size, misses: make(map[matchKey]*list.Element)}
+}
+
+// Is reports the result of Is(err, target), which it does not compute again
+// if it already found that err does not match target. Only the errors and
+// targets whose types are comparable are cached.
+func (c *MatchCache) Is(err, target error) bool {
+	if err == nil || target == nil || !isComparable(err) || !isComparable(target) {
+		return isChain(err, target)
+	}
+	key := matchKey{err, target}
+	c.mu.Lock()
+	c.lookups++
+	if e, ok := c.misses[key]; ok {
+		c.hits++
+		c.lru.MoveToFront(e)
+		c.mu.Unlock()
+		return false
+	}
+	c.mu.Unlock()
+
+	if isChain(err, target) {
+		return true
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.misses[key]; ok || c.size <= 0 {
+		return false
+	}
+	c.misses[key] = c.lru.PushFront(key)
+	if c.lru.Len() > c.size {
+		delete(c.misses, c.lru.Remove(c.lru.Back()).(matchKey))
+	}
+	return false
+}
+
+// HitRate returns the fraction of the calls to Is with cacheable arguments
+// that were answered from the cache, or 0 if there were none.
+func (c *MatchCache) HitRate() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.lookups == 0 {
+		return 0
+	}
+	return float64(c.hits) / float64(c.lookups)
+}
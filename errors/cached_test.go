@@ -0,0 +1,109 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors_test
+
+import (
+	"testing"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/fmt"
+)
+
+// countingErr counts the number of times it is formatted.
+type countingErr struct{ n *int }
+
+func (e countingErr) Error() string { return fmt.Sprint(e) }
+
+func (e countingErr) Format(p errors.Printer) (next error) {
+	*e.n++
+	p.Print("counting")
+	p.Detail()
+	p.Print("detail")
+	return nil
+}
+
+func TestCached(t *testing.T) {
+	if got := errors.Cached(nil); got != nil {
+		t.Errorf("Cached(nil) = %v, want nil", got)
+	}
+
+	n := 0
+	inner := countingErr{&n}
+	err := errors.Cached(fmt.Errorf("wrap: %v", inner))
+	for i := 0; i < 3; i++ {
+		if got, want := err.Error(), "wrap: counting"; got != want {
+			t.Errorf("Error() = %q, want %q", got, want)
+		}
+		if got, want := fmt.Sprintf("%v", err), "wrap: counting"; got != want {
+			t.Errorf("%%v = %q, want %q", got, want)
+		}
+		if got, want := fmt.Sprintf("%q", err), `"wrap: counting"`; got != want {
+			t.Errorf("%%q = %q, want %q", got, want)
+		}
+		if got, want := fmt.Sprintf("%12.6s|%x", err, err), "      wrap: |777261703a20636f756e74696e67"; got != want {
+			t.Errorf("%%12.6s|%%x = %q, want %q", got, want)
+		}
+	}
+	if n != 1 {
+		t.Errorf("inner error formatted %d times, want 1", n)
+	}
+
+	newErr := func(wrap func(error) error) error {
+		return fmt.Errorf("ctx: %w", wrap(fmt.Errorf("wrap: %v", errors.New("x"))))
+	}
+	want := fmt.Sprintf("%+v", newErr(func(err error) error { return err }))
+	if got := fmt.Sprintf("%+v", newErr(errors.Cached)); got != want {
+		t.Errorf("%%+v of a chain = %q, want %q", got, want)
+	}
+
+	var c countingErr
+	if !errors.As(err, &c) {
+		t.Errorf("As(Cached(err), &c) = false, want true")
+	}
+}
+
+// countingIs counts the calls to its Is method.
+type countingIs struct{ n *int }
+
+func (e countingIs) Error() string { return "countingIs" }
+
+func (e countingIs) Is(target error) bool {
+	*e.n++
+	return false
+}
+
+func TestMatchCache(t *testing.T) {
+	n := 0
+	err1 := fmt.Errorf("a: %v", countingIs{&n})
+	err2 := fmt.Errorf("b: %v", countingIs{&n})
+	target := errors.New("target")
+
+	c := errors.NewMatchCache(1)
+	if c.HitRate() != 0 {
+		t.Errorf("HitRate() = %v, want 0", c.HitRate())
+	}
+	for i := 0; i < 2; i++ {
+		if c.Is(err1, target) {
+			t.Errorf("Is(err1, target) = true, want false")
+		}
+	}
+	if n != 1 {
+		t.Errorf("repeated Is: %d calls to Is method, want 1", n)
+	}
+	if got := c.HitRate(); got != 0.5 {
+		t.Errorf("HitRate() = %v, want 0.5", got)
+	}
+
+	// err2 evicts err1.
+	c.Is(err2, target)
+	c.Is(err1, target)
+	if n != 3 {
+		t.Errorf("after eviction: %d calls to Is method, want 3", n)
+	}
+
+	if !c.Is(fmt.Errorf("c: %v", target), target) {
+		t.Errorf("Is(wrapped target, target) = false, want true")
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+// A Category is a named class of errors, such as the transient errors, made
+// of the errors matching any of its member errors with Is or annotated with
+// it by WithCategory.
+type Category struct {
+	name    string
+	members []error
+}
+
+// NewCategory returns a Category with the given name and members.
+func NewCategory(name string, members ...error) *Category {
+	return &Category{name, members}
+}
+
+// Name returns the name of c.
+func (c *Category) Name() string { return c.name }
+
+// Contains reports whether err belongs to c: whether an error of err's chain
+// was annotated with c by WithCategory or matches one of the members of c.
+// A nil error belongs to no category.
+func (c *Category) Contains(err error) bool {
+	if err == nil {
+		return false
+	}
+	found := false
+	Walk(err, func(err error) bool {
+		if w, ok := err.(*withCategory); ok && w.cat == c {
+			found = true
+		}
+		return !found
+	})
+	return found || IsOneOf(err, c.members...)
+}
+
+// CategoryOf returns the first of cats that contains err, and reports whether
+// there is one.
+func CategoryOf(err error, cats ...*Category) (*Category, bool) {
+	for _, c := range cats {
+		if c.Contains(err) {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// WithCategory returns an error that annotates err with cat, so that it
+// belongs to cat whatever the errors of its chain. The returned error
+// formats as err, followed by the name of cat when printed with detail, and
+// unwraps to err. WithCategory returns nil if err is nil.
+func WithCategory(err error, cat *Category) error {
+	if err == nil {
+		return nil
+	}
+	return &withCategory{err, cat}
+}
+
+type withCategory struct {
+	err error
+	cat *Category
+}
+
+func (e *withCategory) Error() string { return e.err.Error() }
+
+func (e *withCategory) Format(p Printer) (next error) {
+	next = formatInPlace(p, e.err)
+	if p.Detail() {
+		p.Printf("category: %s\n", e.cat.name)
+	}
+	return next
+}
+
+func (e *withCategory) Unwrap() error { return e.err }
+
+func (e *withCategory) CloneWrapping(errs []error) error {
+	c := *e
+	c.err = errs[0]
+	return &c
+}
@@ -0,0 +1,52 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors_test
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/fmt"
+)
+
+func TestCategory(t *testing.T) {
+	errAuth := errors.New("unauthorized")
+	transient := errors.NewCategory("transient", os.ErrDeadlineExceeded, io.ErrUnexpectedEOF)
+	auth := errors.NewCategory("auth", errAuth)
+	permanent := errors.NewCategory("permanent")
+
+	testCases := []struct {
+		err  error
+		want *errors.Category
+	}{
+		{nil, nil},
+		{errors.New("x"), nil},
+		{fmt.Errorf("read: %v", io.ErrUnexpectedEOF), transient},
+		{fmt.Errorf("login: %v", errAuth), auth},
+		{errors.Join(errAuth, os.ErrDeadlineExceeded), transient},
+		{fmt.Errorf("wrap: %v", errors.WithCategory(errors.New("bad input"), permanent)), permanent},
+	}
+	for _, tc := range testCases {
+		got, ok := errors.CategoryOf(tc.err, transient, auth, permanent)
+		if got != tc.want || ok != (tc.want != nil) {
+			t.Errorf("CategoryOf(%v) = %v, %v; want %v", tc.err, got, ok, tc.want)
+		}
+	}
+	if errors.WithCategory(nil, permanent) != nil {
+		t.Errorf("WithCategory(nil, permanent) != nil")
+	}
+}
+
+func TestWithCategoryFormat(t *testing.T) {
+	err := errors.WithCategory(errorT{}, errors.NewCategory("permanent"))
+	if got, want := fmt.Sprintf("%v", err), "errorT"; got != want {
+		t.Errorf("%%v: got %q; want %q", got, want)
+	}
+	if got, want := fmt.Sprintf("%+v", err), "errorT:\n    category: permanent\n    "; got != want {
+		t.Errorf("%%+v: got %q; want %q", got, want)
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+// WithCause returns an error that stands for outer, an error of a higher
+// layer typically carrying its own type and fields, and is caused by cause.
+// It prints as the message of outer followed by cause, as in
+// "request rejected: connection reset", and unwraps to cause.
+//
+// Unlike wrapping cause with Errorf, the returned error presents itself as
+// outer: Is and As match outer, and its chain, before cause's chain. The
+// errors wrapped by outer, if any, are not printed.
+// WithCause returns outer if cause is nil, and cause if outer is nil.
+func WithCause(outer, cause error) error {
+	switch {
+	case cause == nil:
+		return outer
+	case outer == nil:
+		return cause
+	}
+	return &withCause{outer, cause}
+}
+
+type withCause struct {
+	outer error
+	cause error
+}
+
+func (e *withCause) Error() string { return Format(e, false) }
+
+func (e *withCause) Format(p Printer) (next error) {
+	p.Print(message(e.outer))
+	if f, ok := e.outer.(Framer); ok {
+		f.Frame().Format(p)
+	}
+	return e.cause
+}
+
+func (e *withCause) Unwrap() error { return e.cause }
+
+func (e *withCause) Is(target error) bool { return isChain(e.outer, target) }
+
+func (e *withCause) As(target interface{}) bool { return as(e.outer, target, Walk) }
+
+func (e *withCause) CloneWrapping(errs []error) error {
+	return &withCause{e.outer, errs[0]}
+}
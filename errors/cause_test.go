@@ -0,0 +1,53 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors_test
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/fmt"
+)
+
+type rejected struct{ code int }
+
+func (e *rejected) Error() string { return fmt.Sprintf("request rejected (%d)", e.code) }
+
+func TestWithCause(t *testing.T) {
+	cause := errors.New("connection reset")
+	outer := &rejected{429}
+	err := errors.WithCause(outer, fmt.Errorf("read: %v", cause))
+
+	if got, want := err.Error(), "request rejected (429): read: connection reset"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	var r *rejected
+	if !errors.As(err, &r) || r != outer {
+		t.Errorf("As(err, &r) = false, want true")
+	}
+	if !errors.Is(err, outer) || !errors.Is(err, cause) {
+		t.Errorf("Is: WithCause does not match both outer and cause")
+	}
+	if got, want := errors.Causes(err), []string{"connection reset", "read", "request rejected (429)"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Causes = %q, want %q", got, want)
+	}
+
+	sentinel := errors.New("not found")
+	err = errors.WithCause(sentinel, errorT{})
+	if got, want := fmt.Sprintf("%v", err), "not found: errorT"; got != want {
+		t.Errorf("%%v = %q, want %q", got, want)
+	}
+	if !errors.Is(err, sentinel) {
+		t.Errorf("Is(err, sentinel) = false, want true")
+	}
+
+	if got := errors.WithCause(outer, nil); got != outer {
+		t.Errorf("WithCause(outer, nil) = %v, want outer", got)
+	}
+	if got := errors.WithCause(nil, cause); got != cause {
+		t.Errorf("WithCause(nil, cause) = %v, want cause", got)
+	}
+}
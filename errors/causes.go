@@ -0,0 +1,84 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import "strings"
+
+// multipleCauses stands for the causes of an error wrapping several errors.
+const multipleCauses = "[multiple causes]"
+
+// Causes returns the messages contributed by the errors in err's chain,
+// ordered from the deepest cause to the outermost error, as in
+// "root cause ... led to ... which caused ...".
+//
+// Each error contributes the message it prints itself, excluding the errors
+// it wraps. The chain is followed until it ends or reaches an error wrapping
+// several errors, which is represented by the placeholder
+// "[multiple causes]". Causes returns nil if err is nil.
+func Causes(err error) []string {
+	msgs, multi := messages(err)
+	if multi {
+		msgs = append(msgs, multipleCauses)
+	}
+	for i, j := 0, len(msgs)-1; i < j; i, j = i+1, j-1 {
+		msgs[i], msgs[j] = msgs[j], msgs[i]
+	}
+	return msgs
+}
+
+// Reverse returns the text of err with the deepest cause first, followed by
+// the errors that wrap it, separated by arrow, as in "low <- mid <- high".
+// An empty arrow means " <- ". The messages are those returned by Causes.
+// Reverse only affects rendering: the chain itself is unchanged.
+func Reverse(err error, arrow string) string {
+	if arrow == "" {
+		arrow = " <- "
+	}
+	return strings.Join(Causes(err), arrow)
+}
+
+// FlattenWithContext returns the errors at the ends of the branches of err's
+// tree, each prefixed with the messages of the errors above it on its
+// branch, so that the errors joined in a tree can be presented as a flat
+// list. For instance, for
+//
+//	fmt.Errorf("sync: %v", errors.Join(fmt.Errorf("a: %v", errA), errB))
+//
+// it returns errors printing as "sync: a: <errA>" and "sync: <errB>".
+//
+// The messages are those returned by Causes, joined by ": ", outermost
+// first. Errors that wrap several errors, such as Join, contribute no
+// message: all their branches share the context accumulated above them. An
+// error reachable through several branches is only reported for the first.
+// Each returned error is the error at the end of its branch with the
+// context added as by Prefix, so that it matches that error with Is and
+// keeps its frames. FlattenWithContext returns nil if err is nil.
+func FlattenWithContext(err error) []error {
+	var w walker
+	var errs []error
+	w.flatten(err, "", &errs)
+	return errs
+}
+
+func (w *walker) flatten(err error, context string, errs *[]error) {
+	for err != nil && !w.visited(err) {
+		if u, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, err := range u.Unwrap() {
+				w.flatten(err, context, errs)
+			}
+			return
+		}
+		msg, next := link(err)
+		if next == nil {
+			if context != "" {
+				err = Prefix(err, context)
+			}
+			*errs = append(*errs, err)
+			return
+		}
+		context += msg + ": "
+		err = next
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors_test
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/fmt"
+)
+
+func TestCauses(t *testing.T) {
+	err1 := errors.New("1")
+	_, errF := os.Open("non-existing")
+	loop := &cyclic{msg: "loop"}
+	loop.next = loop
+
+	testCases := []struct {
+		err  error
+		want []string
+	}{
+		{nil, nil},
+		{err1, []string{"1"}},
+		{fmt.Errorf("b: %v", fmt.Errorf("a: %v", err1)), []string{"1", "a", "b"}},
+		{errors.WithStatus(fmt.Errorf("a: %v", err1), 404), []string{"1", "a"}},
+		{fmt.Errorf("a: %v", multi{err1, err1}), []string{"[multiple causes]", "a"}},
+		{fmt.Errorf("open: %v", errF), []string{"no such file or directory", "open non-existing", "open"}},
+		{&cyclic{"outer", fmt.Errorf("x: %v", err1)}, []string{"outer"}},
+		{&cyclic{"outer: x: 1", fmt.Errorf("x: %v", err1)}, []string{"1", "x", "outer"}},
+		{loop, []string{"loop"}},
+	}
+	for _, tc := range testCases {
+		if got := errors.Causes(tc.err); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Causes(%v) = %q, want %q", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestReverse(t *testing.T) {
+	err := fmt.Errorf("high: %v", fmt.Errorf("mid: %v", errors.New("low")))
+	testCases := []struct {
+		err   error
+		arrow string
+		want  string
+	}{
+		{nil, "", ""},
+		{err, "", "low <- mid <- high"},
+		{err, " < ", "low < mid < high"},
+		{fmt.Errorf("a: %v", multi{err, err}), "", "[multiple causes] <- a"},
+	}
+	for _, tc := range testCases {
+		if got := errors.Reverse(tc.err, tc.arrow); got != tc.want {
+			t.Errorf("Reverse(%v, %q) = %q, want %q", tc.err, tc.arrow, got, tc.want)
+		}
+	}
+}
+
+func TestFlattenWithContext(t *testing.T) {
+	errA := errors.New("A")
+	errB := errors.New("B")
+	err := fmt.Errorf("sync: %v", errors.Join(fmt.Errorf("a: %v", errA), errB))
+
+	got := errors.FlattenWithContext(err)
+	want := []string{"sync: a: A", "sync: B"}
+	if len(got) != len(want) {
+		t.Fatalf("FlattenWithContext: got %v; want %q", got, want)
+	}
+	for i, err := range got {
+		if err.Error() != want[i] {
+			t.Errorf("%d: got %q; want %q", i, err, want[i])
+		}
+	}
+	if !errors.Is(got[0], errA) || !errors.Is(got[1], errB) || errors.Is(got[1], errA) {
+		t.Errorf("FlattenWithContext: errors do not match their own leaf only")
+	}
+	if got := errors.FlattenWithContext(errB); len(got) != 1 || got[0] != errB {
+		t.Errorf("FlattenWithContext(errB) = %v, want [errB]", got)
+	}
+	if got := errors.FlattenWithContext(nil); got != nil {
+		t.Errorf("FlattenWithContext(nil) = %v, want nil", got)
+	}
+}
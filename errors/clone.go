@@ -0,0 +1,90 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import "golang.org/x/exp/errors/internal"
+
+// Clone returns a copy of err's chain whose wrapping errors are new values,
+// so that annotations added to the copy, for instance with WithMeta, are not
+// shared with err.
+//
+// Clone copies the errors of this package and of package errors/fmt that
+// wrap other errors, together with everything they record, such as their
+// messages, frames and IDs, and then copies the errors they wrap in turn.
+// All other errors are shared: errors that wrap nothing, such as sentinel
+// errors created by New, so that Is matches the copy as it matches err;
+// errors of other packages, together with the errors they wrap; and errors
+// that are reachable more than once, from their second occurrence on.
+// Values attached with WithMeta are copied by assignment.
+func Clone(err error) error {
+	var w walker
+	return w.clone(err)
+}
+
+func (w *walker) clone(err error) error {
+	c, ok := err.(internal.Cloner)
+	if !ok || w.visited(err) {
+		return err
+	}
+	var errs []error
+	switch u := err.(type) {
+	case interface{ Unwrap() []error }:
+		for _, err := range u.Unwrap() {
+			errs = append(errs, w.clone(err))
+		}
+	case Wrapper:
+		errs = []error{w.clone(u.Unwrap())}
+	}
+	return c.CloneWrapping(errs)
+}
+
+// CloneFrame returns a copy of err whose outermost frame, the location of the
+// outermost error of the chain that records one, is replaced by the location
+// of the caller of CloneFrame, for errors that are created once and returned
+// from several places, such as cached errors. StackTrace and printing with
+// detail then show each place the error is returned from.
+//
+// Only the outermost frame changes. The errors wrapping the error that
+// records it are copied as by Clone, and the rest of the chain is shared.
+// CloneFrame supports the errors created by New and by the Errorf and WrapIf
+// functions of package errors/fmt, wrapped by other errors of these packages
+// that wrap a single error. It returns err unchanged if err's chain reaches
+// another error first.
+func CloneFrame(err error) error {
+	frame := Caller(1)
+	if c, ok := reframe(err, frame); ok {
+		return c
+	}
+	return err
+}
+
+// A reframer is an error that can be copied with a different frame.
+type reframer interface {
+	Reframe(frame Frame) error
+}
+
+// reframe implements CloneFrame, reporting whether err was reframed.
+func reframe(err error, frame Frame) (error, bool) {
+	if r, ok := err.(reframer); ok {
+		return r.Reframe(frame), true
+	}
+	c, ok := err.(internal.Cloner)
+	if !ok {
+		return err, false
+	}
+	// Errors that have this method wrap several errors, or none.
+	if _, ok := err.(interface{ Unwrap() []error }); ok {
+		return err, false
+	}
+	u, ok := err.(Wrapper)
+	if !ok || u.Unwrap() == nil {
+		return err, false
+	}
+	next, ok := reframe(u.Unwrap(), frame)
+	if !ok {
+		return err, false
+	}
+	return c.CloneWrapping([]error{next}), true
+}
@@ -0,0 +1,96 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors_test
+
+import (
+	"testing"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/fmt"
+)
+
+func TestClone(t *testing.T) {
+	err1 := errors.New("1")
+	err2 := errors.New("2")
+	foreign := &causer{"legacy", err2}
+	mid := errors.WithStatus(fmt.Errorf("mid: %w", err1), 404)
+	err := errors.Join(fmt.Errorf("outer: %v", mid), foreign)
+	err = errors.WithMeta(err, requestID("a"))
+
+	clone := errors.Clone(err)
+	if clone == err {
+		t.Fatalf("Clone returned err itself")
+	}
+	if got, want := fmt.Sprintf("%+v", clone), fmt.Sprintf("%+v", err); got != want {
+		t.Errorf("%%+v of clone:\n got %q\nwant %q", got, want)
+	}
+	for _, target := range []error{err1, err2, foreign} {
+		if !errors.Is(clone, target) {
+			t.Errorf("Is(clone, %v) = false, want true", target)
+		}
+	}
+	if errors.Is(clone, mid) {
+		t.Errorf("Is(clone, mid) = true, want false: wrapper is shared")
+	}
+	if got := errors.Status(clone); got != 404 {
+		t.Errorf("Status(clone) = %d, want 404", got)
+	}
+
+	annotated := errors.WithMeta(clone, requestID("b"))
+	if got, _ := errors.Meta[requestID](err); got != "a" {
+		t.Errorf("Meta(err) = %q, want %q", got, "a")
+	}
+	if got, _ := errors.Meta[requestID](annotated); got != "b" {
+		t.Errorf("Meta(annotated) = %q, want %q", got, "b")
+	}
+
+	if got := errors.Clone(err1); got != err1 {
+		t.Errorf("Clone(err1) = %v, want err1 itself", got)
+	}
+	if got := errors.Clone(nil); got != nil {
+		t.Errorf("Clone(nil) = %v, want nil", got)
+	}
+}
+
+var errCachedSentinel = errors.New("sentinel")
+
+func cachedError() error { return fmt.Errorf("cached: %v", errCachedSentinel) }
+
+func TestCloneFrame(t *testing.T) {
+	function := func(err error) string {
+		f, _, _ := err.(errors.Framer).Frame().Location()
+		return f
+	}
+	const here = "golang.org/x/exp/errors_test.TestCloneFrame"
+
+	orig := cachedError()
+	err := errors.CloneFrame(orig)
+	if got := function(err); got != here {
+		t.Errorf("frame of copy: got %s; want %s", got, here)
+	}
+	if got := function(orig); got != "golang.org/x/exp/errors_test.cachedError" {
+		t.Errorf("frame of original changed to %s", got)
+	}
+	if errors.Unwrap(err) != errCachedSentinel || err.Error() != orig.Error() {
+		t.Errorf("copy = %v wrapping %v; want %v wrapping the sentinel", err, errors.Unwrap(err), orig)
+	}
+
+	status := errors.CloneFrame(errors.WithStatus(orig, 404))
+	if errors.Status(status) != 404 || function(errors.Unwrap(status)) != here {
+		t.Errorf("CloneFrame(WithStatus): status %d, frame %s; want 404, %s", errors.Status(status), function(errors.Unwrap(status)), here)
+	}
+	if got := function(errors.CloneFrame(errCachedSentinel)); got != here {
+		t.Errorf("CloneFrame(New): got %s; want %s", got, here)
+	}
+
+	for _, err := range []error{nil, errorT{}, errors.Join(errCachedSentinel)} {
+		if _, ok := err.(errors.Framer); ok {
+			continue
+		}
+		if got := errors.CloneFrame(err); got != err {
+			t.Errorf("CloneFrame(%v) = %v, want it unchanged", err, got)
+		}
+	}
+}
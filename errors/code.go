@@ -0,0 +1,38 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+// IsCode reports whether any error in err's chain is of type C and equal to
+// code. It is meant for error codes defined as comparable value types
+// implementing error, for which it avoids converting code to an error.
+func IsCode[C comparable](err error, code C) bool {
+	found := false
+	Walk(err, func(err error) bool {
+		if c, ok := err.(C); ok && c == code {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// Capability returns the first error in err's chain, in the order of Walk,
+// that implements T, as a T, and reports whether there is one. T is meant to
+// be an interface describing a capability of errors, as in
+//
+//	if t, ok := errors.Capability[interface{ Temporary() bool }](err); ok && t.Temporary() {
+//		// retry
+//	}
+//
+// Unlike As, Capability does not consult the As methods of the errors.
+func Capability[T any](err error) (T, bool) {
+	var found T
+	ok := false
+	Walk(err, func(err error) bool {
+		found, ok = err.(T)
+		return !ok
+	})
+	return found, ok
+}
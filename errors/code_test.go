@@ -0,0 +1,76 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors_test
+
+import (
+	"strconv"
+	"testing"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/fmt"
+)
+
+type errCode int
+
+func (c errCode) Error() string { return "code " + strconv.Itoa(int(c)) }
+
+func TestIsCode(t *testing.T) {
+	err := fmt.Errorf("wrap: %v", multi{errorT{}, errCode(404)})
+	testCases := []struct {
+		err   error
+		code  errCode
+		match bool
+	}{
+		{nil, 404, false},
+		{errCode(404), 404, true},
+		{errCode(500), 404, false},
+		{err, 404, true},
+		{err, 500, false},
+		{errors.Opaque(errCode(404)), 404, false},
+	}
+	for _, tc := range testCases {
+		if got := errors.IsCode(tc.err, tc.code); got != tc.match {
+			t.Errorf("IsCode(%v, %v) = %v, want %v", tc.err, tc.code, got, tc.match)
+		}
+	}
+	if errors.IsCode(err, errorT{}) != true {
+		t.Errorf("IsCode(err, errorT{}) = false, want true")
+	}
+}
+
+func BenchmarkIsCode(b *testing.B) {
+	err := fmt.Errorf("a: %v", fmt.Errorf("b: %v", errCode(404)))
+	for i := 0; i < b.N; i++ {
+		errors.IsCode(err, errCode(404))
+	}
+}
+
+func BenchmarkIsCodeIs(b *testing.B) {
+	err := fmt.Errorf("a: %v", fmt.Errorf("b: %v", errCode(404)))
+	for i := 0; i < b.N; i++ {
+		errors.Is(err, errCode(404))
+	}
+}
+
+// temporary is an error reporting whether it is temporary.
+type temporary bool
+
+func (e temporary) Error() string   { return "temporary" }
+func (e temporary) Temporary() bool { return bool(e) }
+
+func TestCapability(t *testing.T) {
+	type temporaryer interface{ Temporary() bool }
+
+	err := fmt.Errorf("a: %v", fmt.Errorf("b: %v", temporary(true)))
+	if c, ok := errors.Capability[temporaryer](err); !ok || !c.Temporary() {
+		t.Errorf("Capability(err) = %v, %v; want temporary(true), true", c, ok)
+	}
+	if c, ok := errors.Capability[temporaryer](fmt.Errorf("a: %v", errorT{})); ok || c != nil {
+		t.Errorf("Capability(errorT) = %v, %v; want nil, false", c, ok)
+	}
+	if _, ok := errors.Capability[temporaryer](nil); ok {
+		t.Errorf("Capability(nil) reports true")
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import "golang.org/x/exp/errors/internal"
+
+// A Collector accumulates non-fatal errors, such as warnings, to be reported
+// together. The zero value is an empty Collector ready to use.
+//
+// A Collector is meant for sequential use, for instance by a single request
+// handler. It is not safe for concurrent use unless guarded by a mutex.
+type Collector struct {
+	errs []error
+}
+
+// Add adds err to c. Nil errors are ignored.
+func (c *Collector) Add(err error) {
+	if err != nil {
+		c.errs = append(c.errs, err)
+	}
+}
+
+// Warn adds an error with a message formatted according to a format
+// specifier to c. The error is created like the errors returned by New: it
+// records the location of the caller of Warn.
+func (c *Collector) Warn(format string, a ...interface{}) {
+	c.errs = append(c.errs, newString(internal.Sprintf(format, a...), 1))
+}
+
+// Flush returns the errors added to c since the last flush, joined with Join,
+// and empties c. It returns nil if no errors were added.
+func (c *Collector) Flush() error {
+	err := Join(c.errs...)
+	c.errs = nil
+	return err
+}
+
+// FlushTo flushes c into *errp: if *errp is nil, it is set to the result of
+// Flush, otherwise both are joined. It is typically deferred by a function
+// with a named error result:
+//
+//	defer c.FlushTo(&err)
+func (c *Collector) FlushTo(errp *error) {
+	err := c.Flush()
+	switch {
+	case err == nil:
+	case *errp == nil:
+		*errp = err
+	default:
+		*errp = Join(*errp, err)
+	}
+}
+
+// Drain receives errors from ch until it is closed and returns the non-nil
+// ones joined with Join, in the order received, or nil if there are none.
+// It is meant for collecting the errors sent by concurrent workers.
+//
+// Drain blocks until ch is closed, which the caller is responsible for
+// arranging, typically by closing it once all the senders are done.
+func Drain(ch <-chan error) error {
+	var errs []error
+	for err := range ch {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return Join(errs...)
+}
+
+// A Buffer accumulates errors to be reported together, like a Collector, but
+// reports errors that have the same root cause once, with a count, as in
+// "open b.txt: permission denied (×37)". The zero value is an empty Buffer
+// ready to use. Like a Collector, a Buffer is not safe for concurrent use.
+//
+// Errors are grouped by the message of the innermost error of their chain,
+// as returned by Root. Each group is reported by its first error.
+type Buffer struct {
+	groups []bufferGroup
+	index  map[string]int // index of groups by key
+}
+
+type bufferGroup struct {
+	err   error
+	count int
+}
+
+// Add adds err to b. Nil errors are ignored.
+func (b *Buffer) Add(err error) {
+	if err == nil {
+		return
+	}
+	leaf, _, _ := Root(err)
+	key := message(leaf)
+	if i, ok := b.index[key]; ok {
+		b.groups[i].count++
+		return
+	}
+	if b.index == nil {
+		b.index = make(map[string]int)
+	}
+	b.index[key] = len(b.groups)
+	b.groups = append(b.groups, bufferGroup{err, 1})
+}
+
+// Err returns the errors added to b, joined with Join in the order in which
+// their groups were first added. The error of a group with more than one
+// error is followed by the size of the group, as if with Concat. Err returns
+// nil if no errors were added.
+func (b *Buffer) Err() error {
+	errs := make([]error, len(b.groups))
+	for i, g := range b.groups {
+		errs[i] = g.err
+		if g.count > 1 {
+			errs[i] = Concat(g.err, internal.Sprintf("×%d", g.count))
+		}
+	}
+	return Join(errs...)
+}
@@ -0,0 +1,124 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+// Concat returns an error that adds note to the message of err, for display,
+// without making err a cause of another error: its Error method returns
+// err.Error() + " (" + note + ")", as in "open config: file not found
+// (retrying)". When printed with detail, the note is printed in parentheses
+// after the message of the outermost error of err's chain, before its
+// detail, and not as a separate link of the chain.
+//
+// The returned error unwraps to err, so Is and As match err's chain as if
+// err was used directly. Concat returns nil if err is nil.
+func Concat(err error, note string) error {
+	if err == nil {
+		return nil
+	}
+	return &concat{err, note}
+}
+
+type concat struct {
+	err  error
+	note string
+}
+
+func (e *concat) Error() string { return e.err.Error() + " (" + e.note + ")" }
+
+func (e *concat) Format(p Printer) (next error) {
+	if !detailRequested(p) {
+		p.Print(Format(e.err, false), " (", e.note, ")")
+		return nil
+	}
+	np := &notePrinter{p, e.note, false}
+	next = formatInPlace(np, e.err)
+	np.printNote()
+	return next
+}
+
+func (e *concat) Unwrap() error { return e.err }
+
+func (e *concat) CloneWrapping(errs []error) error {
+	c := *e
+	c.err = errs[0]
+	return &c
+}
+
+// Prefix returns an error that prints as err with prefix prepended to the
+// message of its outermost error, as in "db: " + "open: no such table".
+// Unlike wrapping err with Errorf, Prefix adds neither a message nor a frame
+// to the chain, including when printed with detail.
+//
+// The returned error unwraps to err, so Is and As match err's chain as if
+// err was used directly. Prefix returns nil if err is nil.
+func Prefix(err error, prefix string) error {
+	if err == nil {
+		return nil
+	}
+	return &prefixed{err, prefix}
+}
+
+// WrapKeepFrame returns an error that prints as msg followed by ": " and
+// err, without a frame of its own: when printed with detail, the location
+// shown for the combined message is that of the outermost error of err. It
+// is meant for wrapping at sites whose location is of no interest, such as
+// generic helpers, where wrapping with Errorf would record the helper.
+//
+// Unlike wrapping with Errorf, msg does not form a separate link of the
+// chain: Causes reports it as part of the message of the outermost error of
+// err. The returned error unwraps to err. WrapKeepFrame returns nil if err
+// is nil.
+func WrapKeepFrame(err error, msg string) error {
+	return Prefix(err, msg+": ")
+}
+
+type prefixed struct {
+	err    error
+	prefix string
+}
+
+func (e *prefixed) Error() string { return Format(e, false) }
+
+func (e *prefixed) Format(p Printer) (next error) {
+	p.Print(e.prefix)
+	return formatInPlace(p, e.err)
+}
+
+func (e *prefixed) Unwrap() error { return e.err }
+
+func (e *prefixed) CloneWrapping(errs []error) error {
+	c := *e
+	c.err = errs[0]
+	return &c
+}
+
+// detailRequested reports whether p prints detail, as reported by its Flag
+// method for the plus flag if it has one, like the printers of this package.
+func detailRequested(p Printer) bool {
+	if s, ok := p.(interface{ Flag(c int) bool }); ok {
+		return s.Flag('+')
+	}
+	return true
+}
+
+// notePrinter prints a note after the message of the error being printed,
+// before any detail.
+type notePrinter struct {
+	Printer
+	note    string
+	printed bool
+}
+
+func (p *notePrinter) Detail() bool {
+	p.printNote()
+	return p.Printer.Detail()
+}
+
+func (p *notePrinter) printNote() {
+	if !p.printed {
+		p.printed = true
+		p.Printer.Print(" (", p.note, ")")
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/fmt"
+)
+
+func TestConcat(t *testing.T) {
+	err1 := errors.New("1")
+	wrapped := fmt.Errorf("wrap: %v", err1)
+	testCases := []struct {
+		err  error
+		verb string
+		want string
+	}{
+		{errors.Concat(errorT{}, "note"), "%v", "errorT (note)"},
+		{errors.Concat(errorD{}, "note"), "%v", "errorD (note)"},
+		{errors.Concat(errorD{}, "note"), "%+v", "errorD (note):\n    detail"},
+		{errors.Concat(errors.Opaque(errorD{}), "note"), "%+v", "errorD (note):\n    detail"},
+		{errors.Concat(wrapped, "note"), "%v", "wrap: 1 (note)"},
+		{errors.Concat(wrapped, "note"), "%+v", strings.Replace(fmt.Sprintf("%+v", wrapped), "wrap:", "wrap (note):", 1)},
+		{fmt.Errorf("outer: %v", errors.Concat(errorT{}, "note")), "%v", "outer: errorT (note)"},
+		{fmt.Errorf("outer: %v", errors.Concat(wrapped, "note")), "%v", "outer: wrap: 1 (note)"},
+	}
+	for _, tc := range testCases {
+		if got := fmt.Sprintf(tc.verb, tc.err); got != tc.want {
+			t.Errorf("%s: got %q; want %q", tc.verb, got, tc.want)
+		}
+	}
+
+	err := errors.Concat(wrapped, "note")
+	if got, want := err.Error(), "wrap: 1 (note)"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if !errors.Is(err, err1) || !errors.Is(err, wrapped) {
+		t.Errorf("Is: Concat does not match the original chain")
+	}
+	if errors.Concat(nil, "note") != nil {
+		t.Errorf("Concat(nil, note) != nil")
+	}
+}
+
+func TestPrefix(t *testing.T) {
+	err1 := errors.New("1")
+	wrapped := fmt.Errorf("wrap: %v", err1)
+	err := errors.Prefix(wrapped, "db: ")
+	if got, want := err.Error(), "db: wrap: 1"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if got, want := fmt.Sprintf("%+v", err), "db: "+fmt.Sprintf("%+v", wrapped); got != want {
+		t.Errorf("%%+v:\n got %q\nwant %q", got, want)
+	}
+	if got, want := errors.Causes(err), []string{"1", "db: wrap"}; len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("Causes = %q, want %q", got, want)
+	}
+	if !errors.Is(err, err1) || !errors.Is(err, wrapped) {
+		t.Errorf("Is: Prefix does not match the original chain")
+	}
+	if len(errors.StackTrace(err)) != 2 {
+		t.Errorf("StackTrace: got %d frames, want 2", len(errors.StackTrace(err)))
+	}
+	if errors.Prefix(nil, "db: ") != nil {
+		t.Errorf("Prefix(nil, prefix) != nil")
+	}
+}
+
+func keepFrameHelper(err error) error { return errors.WrapKeepFrame(err, "helper") }
+
+func TestWrapKeepFrame(t *testing.T) {
+	err1 := errors.New("1")
+	err := keepFrameHelper(fmt.Errorf("wrap: %v", err1))
+	if got, want := err.Error(), "helper: wrap: 1"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	got := fmt.Sprintf("%+v", err)
+	re := regexp.MustCompile(`^helper: wrap:\n    golang.org/x/exp/errors_test.TestWrapKeepFrame\n`)
+	if !re.MatchString(got) || strings.Contains(got, "keepFrameHelper") {
+		t.Errorf("%%+v: got %q; want match for %s without helper frame", got, re)
+	}
+	if !errors.Is(err, err1) {
+		t.Errorf("Is(err, err1) = false, want true")
+	}
+	if errors.WrapKeepFrame(nil, "helper") != nil {
+		t.Errorf("WrapKeepFrame(nil, msg) != nil")
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import "strings"
+
+// Contains reports whether the text of any error in err's chain, as returned
+// by its Error method, contains substr. The chain consists of err and the
+// errors reached from it by Walk, including all the errors wrapped by errors
+// that wrap several, but not the errors hidden by Redact.
+//
+// Matching text is a last resort, for errors of external systems that
+// provide nothing else to act on: messages change more readily than error
+// values and types, which should be matched with Is and As when possible.
+func Contains(err error, substr string) bool {
+	return containsFunc(err, func(s string) bool {
+		return strings.Contains(s, substr)
+	})
+}
+
+// ContainsFold is like Contains but ignores case: the texts are compared
+// after mapping both to lower case with strings.ToLower.
+func ContainsFold(err error, substr string) bool {
+	substr = strings.ToLower(substr)
+	return containsFunc(err, func(s string) bool {
+		return strings.Contains(strings.ToLower(s), substr)
+	})
+}
+
+func containsFunc(err error, match func(string) bool) bool {
+	found := false
+	Walk(err, func(err error) bool {
+		if !found && match(err.Error()) {
+			found = true
+		}
+		return !found && !isRedacted(err)
+	})
+	return found
+}
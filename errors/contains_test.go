@@ -0,0 +1,45 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors_test
+
+import (
+	"testing"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/fmt"
+)
+
+func TestContains(t *testing.T) {
+	err := fmt.Errorf("query: %v", errors.Join(errored("Connection Refused"), errors.New("timeout")))
+	hidden := errors.Redact(errors.New("secret table"), "internal error")
+	testCases := []struct {
+		err    error
+		substr string
+		want   bool
+		fold   bool
+	}{
+		{nil, "", false, false},
+		{err, "query", true, true},
+		{err, "Connection Refused", true, true},
+		{err, "connection refused", false, true},
+		{err, "timeout", true, true},
+		{err, "TIMEOUT", false, true},
+		{err, "missing", false, false},
+		{hidden, "secret", false, false},
+		{hidden, "internal", true, true},
+	}
+	for _, tc := range testCases {
+		if got := errors.Contains(tc.err, tc.substr); got != tc.want {
+			t.Errorf("Contains(%v, %q) = %v, want %v", tc.err, tc.substr, got, tc.want)
+		}
+		if got := errors.ContainsFold(tc.err, tc.substr); got != tc.fold {
+			t.Errorf("ContainsFold(%v, %q) = %v, want %v", tc.err, tc.substr, got, tc.fold)
+		}
+	}
+}
+
+type errored string
+
+func (e errored) Error() string { return string(e) }
@@ -0,0 +1,132 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/exp/errors/internal"
+)
+
+// maxThrottled is the maximum number of distinct errors tracked by the
+// throttling set with ThrottleCreate.
+const maxThrottled = 1024
+
+var (
+	onCreate func(err error, count int)
+
+	throttle struct {
+		sync.Mutex
+		window  time.Duration
+		entries map[createKey]*createEntry
+	}
+)
+
+// createKey identifies the errors that are the same for ThrottleCreate: the
+// errors with the same message created at the same location.
+type createKey struct {
+	msg  string
+	file string
+	line int
+}
+
+type createEntry struct {
+	last  time.Time // when the hook was last called
+	count int       // errors created since then
+}
+
+// SetOnCreate sets a function called for each error created by New,
+// NewStack, ErrorfStack, Collector.Warn and the Errorf, ErrorfAt, WrapIf and
+// Rewrap functions of package errors/fmt, for instance to count errors in
+// metrics. It is called with the new error and the number of errors it
+// stands for, which is 1 unless calls are throttled with ThrottleCreate. The
+// errors created by Lazy are not reported, so as not to compute their
+// messages. A nil f, the default, removes the hook.
+//
+// SetOnCreate should be called early, for instance at the start of main,
+// and not concurrently with creating errors. The hook itself may be called
+// concurrently.
+func SetOnCreate(f func(err error, count int)) {
+	onCreate = f
+	if f == nil {
+		internal.OnCreate = nil
+		return
+	}
+	internal.OnCreate = created
+}
+
+// ThrottleCreate limits the calls to the hook set with SetOnCreate to one
+// per window for identical errors, which have the same message and were
+// created at the same location, as reported by their frame, so that a loop
+// creating the same error over and over does not flood the hook. The errors
+// created within window of the last call for an identical error are counted
+// rather than reported, and the count is passed to the hook with the next
+// such error created after the window. At most 1024 distinct errors are
+// tracked: beyond that, errors whose window has elapsed are forgotten, along
+// with their counts, and if none has, new errors are reported without being
+// tracked. A window of 0 or less, the default, disables throttling.
+//
+// Identical errors are recognized by their message and the file and line of
+// their frame, rather than by a hash of the errors they wrap, which would
+// have to be computed for every error created and would tell apart errors
+// created at the same place that wrap different causes.
+//
+// ThrottleCreate should be called early, for instance at the start of main,
+// and not concurrently with creating errors.
+func ThrottleCreate(window time.Duration) {
+	throttle.Lock()
+	defer throttle.Unlock()
+	throttle.window = window
+	throttle.entries = nil
+}
+
+// created calls the hook set with SetOnCreate for err, subject to
+// throttling.
+func created(err error) {
+	if throttle.window <= 0 {
+		onCreate(err, 1)
+		return
+	}
+	key := createKey{msg: err.Error()}
+	if f, ok := err.(Framer); ok {
+		_, key.file, key.line = f.Frame().Location()
+	}
+	if count := throttled(key, time.Now()); count > 0 {
+		onCreate(err, count)
+	}
+}
+
+// throttled records the creation at now of an error identified by key and
+// returns the count to report for it, or 0 if it must not be reported.
+func throttled(key createKey, now time.Time) int {
+	throttle.Lock()
+	defer throttle.Unlock()
+	e, ok := throttle.entries[key]
+	if !ok {
+		if throttle.entries == nil {
+			throttle.entries = make(map[createKey]*createEntry)
+		}
+		if len(throttle.entries) == maxThrottled {
+			for k, e := range throttle.entries {
+				if now.Sub(e.last) >= throttle.window {
+					delete(throttle.entries, k)
+				}
+			}
+			if len(throttle.entries) == maxThrottled {
+				return 1
+			}
+		}
+		throttle.entries[key] = &createEntry{last: now}
+		return 1
+	}
+	e.count++
+	if now.Sub(e.last) < throttle.window {
+		return 0
+	}
+	count := e.count
+	e.last, e.count = now, 0
+	return count
+}
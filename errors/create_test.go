@@ -0,0 +1,75 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/fmt"
+)
+
+func newSame() error { return errors.New("same") }
+
+func TestSetOnCreate(t *testing.T) {
+	var msgs []string
+	var counts []int
+	errors.SetOnCreate(func(err error, count int) {
+		msgs = append(msgs, err.Error())
+		counts = append(counts, count)
+	})
+	defer errors.SetOnCreate(nil)
+
+	err := errors.New("1")
+	err = fmt.Errorf("a: %v", err)
+	err = fmt.Errorf("b: %w", err)
+	err = fmt.WrapIf(true, err, "c")
+	fmt.WrapIf(false, err, "d")
+	fmt.Rewrap(err, "e")
+	errors.Lazy(func() string { return "lazy" })
+	errors.NewStack(0, 2, "stack")
+	var c errors.Collector
+	c.Warn("warn %d", 1)
+	want := []string{"1", "a: 1", "b: a: 1", "c: b: a: 1", "e: b: a: 1", "stack", "warn 1"}
+	if !reflect.DeepEqual(msgs, want) || !reflect.DeepEqual(counts, []int{1, 1, 1, 1, 1, 1, 1}) {
+		t.Errorf("hook calls: got %q, %v; want %q, all 1", msgs, counts, want)
+	}
+
+	errors.SetOnCreate(nil)
+	msgs = nil
+	errors.New("2")
+	if msgs != nil {
+		t.Errorf("hook removed: got calls for %q", msgs)
+	}
+}
+
+func TestThrottleCreate(t *testing.T) {
+	var counts []int
+	errors.SetOnCreate(func(err error, count int) { counts = append(counts, count) })
+	defer errors.SetOnCreate(nil)
+	defer errors.ThrottleCreate(0)
+
+	errors.ThrottleCreate(time.Hour)
+	for i := 0; i < 5; i++ {
+		newSame()
+	}
+	errors.New("same")
+	if want := []int{1, 1}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("within the window: counts %v, want %v", counts, want)
+	}
+
+	counts = nil
+	errors.ThrottleCreate(10 * time.Millisecond)
+	for i := 0; i < 3; i++ {
+		newSame()
+	}
+	time.Sleep(20 * time.Millisecond)
+	newSame()
+	if want := []int{1, 3}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("after the window: counts %v, want %v", counts, want)
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import "fmt"
+
+// IsDebug is like Is but also describes each error of err's chain that was
+// compared against target, in order, to help understand unexpected results.
+// Each description gives the type and the message of the error, whether its
+// Is method was consulted and whether it matched, as in
+//
+//	*errors.errorString "not found" matched
+//
+// IsDebug examines the same errors in the same order as Is. Like Is, it
+// does not consult the Is methods of the errors of a chain made of errors of
+// this package and of package errors/fmt that only match by being equal, nor
+// when target implements Target.
+func IsDebug(err, target error) (ok bool, trace []string) {
+	_, isTarget := target.(Target)
+	isMethods := !isTarget && !plainMatch(err)
+	stopped := false
+	walk := func(err error, visit func(error) bool) {
+		Walk(err, func(err error) bool {
+			if stopped {
+				return false
+			}
+			descend := visit(err)
+			stopped = !descend
+			trace = append(trace, describeMatch(err, target, isMethods, stopped))
+			return descend
+		})
+	}
+	_, ok = isNode(err, target, walk)
+	return ok, trace
+}
+
+// describeMatch describes err as compared against target by IsDebug;
+// isMethods reports whether Is consults the Is methods of the chain.
+func describeMatch(err, target error, isMethods, matched bool) string {
+	s := fmt.Sprintf("%T %q", err, message(err))
+	if _, ok := err.(interface{ Is(error) bool }); ok && isMethods && !(isComparable(target) && err == target) {
+		s += " (Is method consulted)"
+	}
+	if matched {
+		s += " matched"
+	}
+	return s
+}
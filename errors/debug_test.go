@@ -0,0 +1,54 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors_test
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/fmt"
+)
+
+func TestIsDebug(t *testing.T) {
+	err1 := errors.New("1")
+	err := fmt.Errorf("a: %v", errors.Join(errorIs("errorT"), err1))
+
+	ok, trace := errors.IsDebug(err, errorT{})
+	want := []string{
+		`*fmt.withChain "a"`,
+		`*errors.joinError "errorT; 1"`,
+		`errors_test.errorIs "errorT" (Is method consulted) matched`,
+	}
+	if !ok || !reflect.DeepEqual(trace, want) {
+		t.Errorf("IsDebug(err, errorT{}) = %v, %q; want true, %q", ok, trace, want)
+	}
+
+	ok, trace = errors.IsDebug(err, err1)
+	want = []string{
+		`*fmt.withChain "a"`,
+		`*errors.joinError "errorT; 1"`,
+		`errors_test.errorIs "errorT" (Is method consulted)`,
+		`*errors.errorString "1" matched`,
+	}
+	if !ok || !reflect.DeepEqual(trace, want) {
+		t.Errorf("IsDebug(err, err1) = %v, %q; want true, %q", ok, trace, want)
+	}
+
+	target := errors.New("other")
+	ok, trace = errors.IsDebug(err1, target)
+	want = []string{`*errors.errorString "1"`}
+	if ok || !reflect.DeepEqual(trace, want) {
+		t.Errorf("IsDebug(err1, other) = %v, %q; want false, %q", ok, trace, want)
+	}
+	ok, trace = errors.IsDebug(errorIs("1"), statusMatcher(404))
+	want = []string{`errors_test.errorIs "1"`}
+	if ok || !reflect.DeepEqual(trace, want) {
+		t.Errorf("IsDebug(errorIs, Target) = %v, %q; want false, %q", ok, trace, want)
+	}
+	if ok := errors.Is(err, errorT{}); !ok {
+		t.Errorf("Is(err, errorT{}) = false, want true")
+	}
+}
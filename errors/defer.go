@@ -0,0 +1,50 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+// Defer calls fn and, if it fails, records its error in *errp. It is meant
+// to be deferred by a function with a named error result, so that errors of
+// cleanup functions are not discarded:
+//
+//	defer errors.Defer(&err, f.Close)
+//
+// The error of fn is wrapped in an error with the message "cleanup failed",
+// which records the location of the function deferring Defer. If *errp is
+// nil, it is set to that error, otherwise both are joined with Join.
+func Defer(errp *error, fn func() error) {
+	cerr := fn()
+	if cerr == nil {
+		return
+	}
+	err := &cleanupError{cerr, Caller(1)}
+	if *errp == nil {
+		*errp = err
+	} else {
+		*errp = Join(*errp, err)
+	}
+}
+
+type cleanupError struct {
+	err   error
+	frame Frame
+}
+
+func (e *cleanupError) Error() string { return Format(e, false) }
+
+func (e *cleanupError) Format(p Printer) (next error) {
+	p.Print("cleanup failed")
+	e.frame.Format(p)
+	return e.err
+}
+
+func (e *cleanupError) Frame() Frame { return e.frame }
+
+func (e *cleanupError) Unwrap() error { return e.err }
+
+func (e *cleanupError) CloneWrapping(errs []error) error {
+	c := *e
+	c.err = errs[0]
+	return &c
+}
@@ -0,0 +1,50 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors_test
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/fmt"
+)
+
+var errClose = errors.New("close")
+
+func withCleanup(work, cleanup error) (err error) {
+	defer errors.Defer(&err, func() error { return cleanup })
+	return work
+}
+
+func TestDefer(t *testing.T) {
+	errWork := errors.New("work")
+	testCases := []struct {
+		work, cleanup error
+		want          string
+	}{
+		{nil, nil, "<nil>"},
+		{errWork, nil, "work"},
+		{nil, errClose, "cleanup failed: close"},
+		{errWork, errClose, "work; cleanup failed: close"},
+	}
+	for _, tc := range testCases {
+		err := withCleanup(tc.work, tc.cleanup)
+		if got := fmt.Sprint(err); got != tc.want {
+			t.Errorf("withCleanup(%v, %v) = %q, want %q", tc.work, tc.cleanup, got, tc.want)
+		}
+		if tc.work != nil && !errors.Is(err, tc.work) {
+			t.Errorf("withCleanup(%v, %v): does not match work error", tc.work, tc.cleanup)
+		}
+		if tc.cleanup != nil && !errors.Is(err, tc.cleanup) {
+			t.Errorf("withCleanup(%v, %v): does not match cleanup error", tc.work, tc.cleanup)
+		}
+	}
+
+	got := fmt.Sprintf("%+v", withCleanup(nil, errClose))
+	if !strings.Contains(got, "errors_test.withCleanup") || !strings.Contains(got, "defer_test.go:") {
+		t.Errorf("%%+v: got %q; want frame of withCleanup", got)
+	}
+}
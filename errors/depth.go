@@ -0,0 +1,55 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import "golang.org/x/exp/errors/internal"
+
+// Depth returns the number of errors in err's chain, or 0 if err is nil.
+// For an error wrapping several errors, the depth is one more than the
+// largest depth of the wrapped errors.
+//
+// Depth uses the method
+//
+//	Depth() int
+//
+// when an error in the chain has one: errors created by this package and by
+// package errors/fmt record their depth at construction, making Depth O(1)
+// for them. Other chains are walked.
+func Depth(err error) int {
+	var w walker
+	return w.depth(err)
+}
+
+func (w *walker) depth(err error) int {
+	n := 0
+	for err != nil && !w.visited(err) {
+		switch u := err.(type) {
+		case interface{ Depth() int }:
+			return n + u.Depth()
+		case interface{ Unwrap() []error }:
+			max := 0
+			for _, err := range u.Unwrap() {
+				if d := w.depth(err); d > max {
+					max = d
+				}
+			}
+			return n + 1 + max
+		}
+		n++
+		err = Unwrap(err)
+	}
+	return n
+}
+
+// SetChainWarnThreshold sets a diagnostic hook for runaway wrapping: warn is
+// called with the depth of the new chain whenever the Errorf function of
+// package errors/fmt creates a chain with more than n errors. Passing a nil
+// warn removes the hook, which is the default.
+//
+// SetChainWarnThreshold should be called during program initialization.
+func SetChainWarnThreshold(n int, warn func(depth int)) {
+	internal.WarnDepth = n
+	internal.Warn = warn
+}
@@ -0,0 +1,67 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors_test
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/fmt"
+)
+
+func TestDepth(t *testing.T) {
+	err1 := errors.New("1")
+	erra := fmt.Errorf("wrap 2: %v", err1)
+	loop := &cyclic{msg: "loop"}
+	loop.next = &cyclic{"loop 2", loop}
+
+	testCases := []struct {
+		err  error
+		want int
+	}{
+		{nil, 0},
+		{err1, 1},
+		{fmt.Errorf("simple"), 1},
+		{erra, 2},
+		{fmt.Errorf("wrap 3: %v", erra), 3},
+		{errorT{}, 1},
+		{&cyclic{"foreign", erra}, 3},
+		{fmt.Errorf("wrap: %v", &cyclic{"foreign", erra}), 4},
+		{errors.Opaque(erra), 1},
+		{multi{err1, erra, nil}, 3},
+		{errors.Join(err1, erra), 3},
+		{fmt.Errorf("wrap: %v", errors.Join(err1, erra)), 4},
+		{loop, 2},
+	}
+	for _, tc := range testCases {
+		if got := errors.Depth(tc.err); got != tc.want {
+			t.Errorf("Depth(%v) = %d, want %d", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestChainWarnThreshold(t *testing.T) {
+	var depths []int
+	errors.SetChainWarnThreshold(3, func(depth int) {
+		depths = append(depths, depth)
+	})
+	defer errors.SetChainWarnThreshold(0, nil)
+
+	var err error = &cyclic{"foreign", errors.New("leaf")}
+	for i := 0; i < 3; i++ {
+		err = fmt.Errorf("wrap: %v", err)
+	}
+	fmt.Errorf("not wrapping: %d", 1)
+	if want := []int{4, 5}; !reflect.DeepEqual(depths, want) {
+		t.Errorf("warned for depths %v, want %v", depths, want)
+	}
+
+	errors.SetChainWarnThreshold(0, nil)
+	fmt.Errorf("wrap: %v", err)
+	if len(depths) != 2 {
+		t.Errorf("warned after removing the hook")
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/exp/errors/internal"
+)
+
+// DetailString returns the detail that Format adds to the text of err when
+// detail is requested, such as the locations of the errors, without their
+// messages, so that a summary and the detail of an error can be logged
+// separately. The detail of each error of the chain printed by Format is
+// given in turn, without indentation, each ending with a newline. For an
+// error that implements fmt.Formatter rather than Formatter, that is what it
+// prints for %+v after what it prints for %v. For an
+// error created by Join, whose detail lists the joined errors with their
+// messages, the detail of each of the joined errors is given instead.
+// DetailString returns "" if no error prints any detail.
+func DetailString(err error) string {
+	var b strings.Builder
+	var w walker
+	w.detailString(&b, err)
+	return b.String()
+}
+
+func (w *walker) detailString(b *strings.Builder, err error) {
+	for err != nil && !w.visited(err) {
+		if j, ok := err.(*joinError); ok {
+			for _, err := range j.errs {
+				w.detailString(b, err)
+			}
+			return
+		}
+		p := &detailPrinter{}
+		switch v := err.(type) {
+		case Formatter:
+			err = v.Format(p)
+		case interface{ FormatError(Printer) error }:
+			err = v.FormatError(p)
+		case fmt.Formatter:
+			p.buf.WriteString(formatterDetail(v))
+			err = nil
+		default:
+			err = nil
+		}
+		if p.buf.Len() > 0 {
+			b.WriteString(p.buf.String())
+			if !strings.HasSuffix(p.buf.String(), "\n") {
+				b.WriteByte('\n')
+			}
+		}
+	}
+}
+
+// formatterDetail returns the detail of an error that implements
+// fmt.Formatter rather than Formatter: the text it prints for %+v after the
+// text it prints for %v, if the former starts with the latter.
+func formatterDetail(v fmt.Formatter) string {
+	plus, plain := fmt.Sprintf("%+v", v), fmt.Sprintf("%v", v)
+	if !strings.HasPrefix(plus, plain) {
+		return ""
+	}
+	return strings.TrimLeft(plus[len(plain):], "\n")
+}
+
+// detailPrinter implements Printer, and fmt.State, recording only what is
+// printed after Detail is called.
+type detailPrinter struct {
+	buf      strings.Builder
+	inDetail bool
+}
+
+func (p *detailPrinter) Print(args ...interface{}) {
+	if p.inDetail {
+		p.buf.WriteString(internal.Sprint(args...))
+	}
+}
+
+func (p *detailPrinter) Printf(format string, args ...interface{}) {
+	if p.inDetail {
+		p.buf.WriteString(internal.Sprintf(format, args...))
+	}
+}
+
+func (p *detailPrinter) Detail() bool {
+	p.inDetail = true
+	return true
+}
+
+func (p *detailPrinter) Write(b []byte) (n int, err error) {
+	if p.inDetail {
+		p.buf.Write(b)
+	}
+	return len(b), nil
+}
+
+func (p *detailPrinter) Width() (wid int, ok bool)      { return 0, false }
+func (p *detailPrinter) Precision() (prec int, ok bool) { return 0, false }
+func (p *detailPrinter) Flag(c int) bool                { return c == '+' }
@@ -0,0 +1,321 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// The binary encoding of an error is a version byte followed by the
+// encoding of the first error printed for it, where the encoding of an
+// error is
+//
+//	flags   byte: bit 0 set if detail follows, bits 1-2 the kind of link,
+//	        bit 3 set if a frame follows
+//	message uvarint length, bytes
+//	frame   function and file, each a uvarint length and bytes, and line,
+//	        a uvarint
+//	detail  uvarint length, bytes, without indentation and without the
+//	        frame
+//	link    for linkNext, the encoding of the next error; for linkMulti,
+//	        a uvarint count followed by the encoding of each error
+const (
+	encodingVersion = 1
+
+	flagDetail = 1 << 0
+	linkShift  = 1
+	linkMask   = 3 << linkShift
+	flagFrame  = 1 << 3
+
+	linkNone  = 0
+	linkNext  = 1
+	linkMulti = 2
+)
+
+var errMalformed = New("errors: malformed binary encoding")
+
+// maxDecodeDepth is the maximum nesting of the errors decoded by
+// UnmarshalBinary, which bounds the recursion on malicious input.
+const maxDecodeDepth = 10000
+
+// MarshalBinary encodes err's chain for transmission to another process,
+// which can decode it with UnmarshalBinary. It returns nil if err is nil.
+//
+// The encoding records, for each error printed by Format, its message, the
+// function, file and line of its frame, if it implements Framer and its
+// detail starts with its frame, and the rest of its detail as text. Errors
+// that wrap several errors are encoded with each of the errors they wrap.
+// Nothing else is recorded: in particular, the types and identities of the
+// errors are lost, as is the detail of errors that implement fmt.Formatter
+// rather than Formatter.
+func MarshalBinary(err error) ([]byte, error) {
+	if err == nil {
+		return nil, nil
+	}
+	var w walker
+	return w.encode([]byte{encodingVersion}, err), nil
+}
+
+func (w *walker) encode(b []byte, err error) []byte {
+	p := &printer{}
+	next := p.formatNode(err)
+	if next != nil && w.visited(next) {
+		next = nil
+	}
+	msg := p.buf
+	p = &printer{detail: true}
+	p.formatNode(err)
+	var detail []byte
+	var loc *location
+	if header := append(msg[:len(msg):len(msg)], ':'); bytes.HasPrefix(p.buf, header) {
+		detail = p.buf[len(header):]
+		loc, detail = cutFrame(err, detail)
+		detail = bytes.TrimPrefix(detail, detailSep)
+		detail = bytes.ReplaceAll(detail, detailSep, []byte("\n"))
+	}
+
+	// The errors wrapped by an error that wraps several errors are found by
+	// unwrapping the errors, such as those created by WithStatus, that format
+	// as the error they wrap.
+	var multi []error
+	var seen walker
+	for e := err; e != nil && next == nil && !seen.visited(e); e = Unwrap(e) {
+		if u, ok := e.(interface{ Unwrap() []error }); ok {
+			multi = u.Unwrap()
+			break
+		}
+	}
+
+	var flags byte
+	switch {
+	case next != nil:
+		flags = linkNext << linkShift
+	case multi != nil:
+		flags = linkMulti << linkShift
+	}
+	if len(detail) > 0 {
+		flags |= flagDetail
+	}
+	if loc != nil {
+		flags |= flagFrame
+	}
+	b = append(b, flags)
+	b = appendBytes(b, msg)
+	if loc != nil {
+		b = appendBytes(b, []byte(loc.function))
+		b = appendBytes(b, []byte(loc.file))
+		b = appendUvarint(b, uint64(loc.line))
+	}
+	if len(detail) > 0 {
+		b = appendBytes(b, detail)
+	}
+
+	switch {
+	case next != nil:
+		b = w.encode(b, next)
+	case multi != nil:
+		b = appendUvarint(b, uint64(len(multi)))
+		for _, err := range multi {
+			b = w.encode(b, err)
+		}
+	}
+	return b
+}
+
+// cutFrame returns the location of the frame of err, if err implements Framer
+// and detail, the detail printed for err, starts with its frame, and the rest
+// of detail. Otherwise, it returns nil and detail unchanged.
+func cutFrame(err error, detail []byte) (*location, []byte) {
+	f, ok := err.(Framer)
+	if !ok {
+		return nil, detail
+	}
+	fr := f.Frame()
+	function, file, line := fr.Location()
+	if function == "" && file == "" {
+		return nil, detail
+	}
+	p := &printer{detail: true}
+	fr.Format(p)
+	frame := bytes.TrimPrefix(p.buf, []byte(":"))
+	if !bytes.HasPrefix(detail, frame) {
+		return nil, detail
+	}
+	return &location{function, file, line}, detail[len(frame):]
+}
+
+func appendUvarint(b []byte, x uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], x)
+	return append(b, buf[:n]...)
+}
+
+func appendBytes(b []byte, s []byte) []byte {
+	b = appendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// UnmarshalBinary decodes an error encoded with MarshalBinary. It returns a
+// nil error if data is empty.
+//
+// The decoded errors print as the original ones, with and without detail,
+// and wrap each other as the printed errors did. The decoded errors whose
+// frames were transmitted implement Framer, and their frames report the
+// original function, file and line, so that StackTrace and Fingerprint treat
+// them as the original errors; as they have no program counter in the
+// decoding process, FrameModePC prints them with their file and line. As the
+// types and identities of the original errors are not transmitted, Is and As
+// do not match the decoded errors against the sentinel errors and types of
+// the decoding process. Encodings of errors nested more than 10000 levels
+// deep are rejected as malformed.
+func UnmarshalBinary(data []byte) (error, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	if data[0] != encodingVersion {
+		return nil, New(fmt.Sprintf("errors: unsupported binary encoding version %d", data[0]))
+	}
+	d := decoder{data: data[1:]}
+	err := d.decode()
+	if d.err != nil {
+		return nil, d.err
+	}
+	if len(d.data) != 0 {
+		return nil, errMalformed
+	}
+	return err, nil
+}
+
+// A decoder decodes errors from data, recording the first failure in err.
+type decoder struct {
+	data  []byte
+	err   error
+	depth int // nesting of the error being decoded
+}
+
+func (d *decoder) decode() error {
+	d.depth++
+	defer func() { d.depth-- }()
+	if d.depth > maxDecodeDepth {
+		d.fail()
+		return nil
+	}
+	flags := d.byte()
+	e := &remoteError{msg: d.string()}
+	if flags&flagFrame != 0 {
+		loc := &location{function: d.string(), file: d.string()}
+		line := d.uvarint()
+		if line > math.MaxInt32 {
+			d.fail()
+		}
+		loc.line = int(line)
+		e.frame = Frame{loc: loc}
+	}
+	if flags&flagDetail != 0 {
+		e.detail = d.string()
+	}
+	if d.err != nil {
+		return nil
+	}
+	if flags&^(flagDetail|linkMask|flagFrame) != 0 {
+		d.fail()
+		return nil
+	}
+	switch (flags & linkMask) >> linkShift {
+	case linkNone:
+	case linkNext:
+		e.next = d.decode()
+	case linkMulti:
+		n := d.uvarint()
+		// Each error takes at least two bytes.
+		if n > uint64(len(d.data))/2 {
+			d.fail()
+			return nil
+		}
+		errs := make([]error, 0, n)
+		for i := uint64(0); i < n && d.err == nil; i++ {
+			errs = append(errs, d.decode())
+		}
+		return &remoteErrors{*e, errs}
+	default:
+		d.fail()
+	}
+	return e
+}
+
+func (d *decoder) byte() byte {
+	if len(d.data) == 0 {
+		d.fail()
+		return 0
+	}
+	c := d.data[0]
+	d.data = d.data[1:]
+	return c
+}
+
+func (d *decoder) uvarint() uint64 {
+	x, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.fail()
+		return 0
+	}
+	d.data = d.data[n:]
+	return x
+}
+
+func (d *decoder) string() string {
+	n := d.uvarint()
+	if n > uint64(len(d.data)) {
+		d.fail()
+		return ""
+	}
+	s := string(d.data[:n])
+	d.data = d.data[n:]
+	return s
+}
+
+func (d *decoder) fail() {
+	if d.err == nil {
+		d.err = errMalformed
+	}
+	d.data = nil
+}
+
+// remoteError is an error decoded by UnmarshalBinary.
+type remoteError struct {
+	msg    string
+	frame  Frame
+	detail string
+	next   error
+}
+
+func (e *remoteError) Error() string { return Format(e, false) }
+
+func (e *remoteError) Format(p Printer) (next error) {
+	p.Print(e.msg)
+	e.frame.Format(p)
+	if e.detail != "" && p.Detail() {
+		p.Print(e.detail)
+	}
+	return e.next
+}
+
+func (e *remoteError) Frame() Frame { return e.frame }
+
+func (e *remoteError) Unwrap() error { return e.next }
+
+// remoteErrors is an error decoded by UnmarshalBinary that wrapped several
+// errors.
+type remoteErrors struct {
+	remoteError
+	errs []error
+}
+
+func (e *remoteErrors) Error() string { return Format(e, false) }
+
+func (e *remoteErrors) Unwrap() []error { return e.errs }
@@ -0,0 +1,123 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors_test
+
+import (
+	"bytes"
+	"testing"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/errorstest"
+	"golang.org/x/exp/errors/fmt"
+)
+
+func TestMarshalBinary(t *testing.T) {
+	err1 := errors.New("1")
+	testCases := []error{
+		err1,
+		errorT{},
+		errorD{},
+		fmt.Errorf("wrap: %v", err1),
+		fmt.Errorf("wrap: %v", errors.WithStatus(fmt.Errorf("mid: %v", err1), 404)),
+		fmt.Errorf("one %w", err1),
+		fmt.Errorf("a: %v", errors.Join(err1, fmt.Errorf("b: %v", errorT{}))),
+		fmt.Errorf("%w and %w", err1, errorT{}),
+	}
+	for _, err := range testCases {
+		data, merr := errors.MarshalBinary(err)
+		if merr != nil {
+			t.Errorf("MarshalBinary(%v): %v", err, merr)
+			continue
+		}
+		got, uerr := errors.UnmarshalBinary(data)
+		if uerr != nil {
+			t.Errorf("UnmarshalBinary(MarshalBinary(%v)): %v", err, uerr)
+			continue
+		}
+		for _, verb := range []string{"%v", "%+v"} {
+			if g, w := fmt.Sprintf(verb, got), fmt.Sprintf(verb, err); g != w {
+				t.Errorf("%s of decoded error:\n got %q\nwant %q", verb, g, w)
+			}
+		}
+		if errors.Is(got, err1) {
+			t.Errorf("Is(decoded, err1) = true, want false")
+		}
+	}
+}
+
+func TestMarshalBinaryMulti(t *testing.T) {
+	err := errors.Join(errors.New("a"), fmt.Errorf("b: %v", errorT{}))
+	data, _ := errors.MarshalBinary(err)
+	got, _ := errors.UnmarshalBinary(data)
+	var n int
+	errors.Walk(got, func(error) bool { n++; return true })
+	if n != 4 {
+		t.Errorf("decoded Join: walked %d errors, want 4", n)
+	}
+}
+
+func TestMarshalBinaryFrames(t *testing.T) {
+	err := fmt.Errorf("copy %w to %w", errors.New("a"), fmt.Errorf("b: %w", errors.New("c")))
+	data, _ := errors.MarshalBinary(err)
+	got, _ := errors.UnmarshalBinary(data)
+	want := errors.StackTrace(err)
+	frames := errors.StackTrace(got)
+	if len(frames) != len(want) {
+		t.Fatalf("StackTrace(decoded) has %d frames, want %d", len(frames), len(want))
+	}
+	for i, f := range frames {
+		if f.PC() != 0 {
+			t.Errorf("frame %d of decoded error: PC() = %#x, want 0", i, f.PC())
+		}
+		function, file, line := f.Location()
+		wfunction, wfile, wline := want[i].Location()
+		if function != wfunction || file != wfile || line != wline {
+			t.Errorf("frame %d of decoded error at %s %s:%d, want %s %s:%d",
+				i, function, file, line, wfunction, wfile, wline)
+		}
+	}
+	errorstest.AssertFrames(t, got, "TestMarshalBinaryFrames")
+	if !errors.SameFingerprint(got, err) {
+		t.Errorf("SameFingerprint(decoded, err) = false, want true")
+	}
+}
+
+func TestUnmarshalBinaryNil(t *testing.T) {
+	data, err := errors.MarshalBinary(nil)
+	if data != nil || err != nil {
+		t.Errorf("MarshalBinary(nil) = %q, %v; want nil, nil", data, err)
+	}
+	if got, err := errors.UnmarshalBinary(nil); got != nil || err != nil {
+		t.Errorf("UnmarshalBinary(nil) = %v, %v; want nil, nil", got, err)
+	}
+}
+
+func TestUnmarshalBinaryMalformed(t *testing.T) {
+	data, _ := errors.MarshalBinary(fmt.Errorf("a: %v", errors.Join(errors.New("b"), errors.New("c"))))
+	testCases := [][]byte{
+		{2},
+		{1},
+		{1, 0, 5, 'a'},
+		{1, 6, 0},
+		{1, 16, 0},
+		{1, 8, 1, 'a', 1, 'f'},
+		append(append([]byte{}, data...), 0),
+		// Too deeply nested: each error links to the next one.
+		append(append([]byte{1}, bytes.Repeat([]byte{2, 1, 'a'}, 10001)...), 0, 1, 'a'),
+	}
+	for i := 1; i < len(data); i++ {
+		testCases = append(testCases, data[:i])
+	}
+	for _, b := range testCases {
+		if got, err := errors.UnmarshalBinary(b); err == nil {
+			t.Errorf("UnmarshalBinary(%q) = %v, nil; want error", b, got)
+		}
+	}
+
+	deep := append(append([]byte{1}, bytes.Repeat([]byte{2, 1, 'a'}, 9999)...), 0, 1, 'a')
+	if _, err := errors.UnmarshalBinary(deep); err != nil {
+		t.Errorf("UnmarshalBinary(10000 nested errors): %v", err)
+	}
+}
@@ -10,10 +10,13 @@
 // This is an EXPERIMENTAL package, and may change in arbitrary ways without notice.
 package errors
 
+import "golang.org/x/exp/errors/internal"
+
 // errorString is a trivial implementation of error.
 type errorString struct {
 	s     string
 	frame Frame
+	id    string
 }
 
 // New returns an error that formats as the given text.
@@ -21,15 +24,70 @@ type errorString struct {
 // The returned error embeds a Frame set to the caller's location and implements
 // Formatter to show this information when printed with details.
 func New(text string) error {
-	return &errorString{text, Caller(1)}
+	return newString(text, 1)
+}
+
+// newString implements New and Collector.Warn, recording the frame skip
+// frames above the caller of newString.
+func newString(text string, skip int) error {
+	return internal.Created(&errorString{text, sampledCaller(skip + 1), internal.NewID()})
 }
 
 func (e *errorString) Error() string {
+	if renderOptions != (RenderOptions{}) {
+		return Format(e, false)
+	}
 	return e.s
 }
 
 func (e *errorString) Format(p Printer) (next error) {
 	p.Print(e.s)
 	e.frame.Format(p)
+	internal.FormatID(p, e.id)
 	return nil
 }
+
+// Is reports whether target is an error created by New with the same text,
+// if enabled with SetSentinelMatchByValue.
+func (e *errorString) Is(target error) bool {
+	t, ok := target.(*errorString)
+	return ok && internal.SentinelMatchByValue && t.s == e.s
+}
+
+// SetSentinelMatchByValue sets whether Is matches errors created by New, or
+// by the Errorf function of package errors/fmt without wrapping, by value
+// rather than by identity.
+//
+// By default, as for the errors of the standard library, such an error only
+// matches itself: a copy of a sentinel error, as in
+//
+//	err := *ErrNotFound
+//
+// or an error with the same text created by another call, does not match
+// it. Matching by value makes such errors match any error of the same kind
+// with the same text, which is convenient for errors that are copied or
+// decoded, but also makes unrelated errors that happen to share a message
+// indistinguishable. It should be set early, before errors are inspected.
+func SetSentinelMatchByValue(byValue bool) {
+	internal.SentinelMatchByValue = byValue
+}
+
+func (e *errorString) PlainMatch() bool { return true }
+
+func (e *errorString) Frame() Frame {
+	return e.frame
+}
+
+func (e *errorString) Reframe(frame Frame) error {
+	c := *e
+	c.frame = frame
+	return &c
+}
+
+func (e *errorString) Depth() int {
+	return 1
+}
+
+func (e *errorString) ID() string {
+	return e.id
+}
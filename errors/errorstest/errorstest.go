@@ -0,0 +1,45 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package errorstest provides utilities for testing code that creates errors
+// with package golang.org/x/exp/errors.
+package errorstest
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/errors"
+)
+
+// AssertFrames reports a test failure unless the frames of err's chain, as
+// returned by errors.StackTrace, include frames of the functions named by
+// wantFunctions, in that order: the outermost error first. Other frames may
+// occur before, between or after them.
+//
+// A name matches a frame if it is the frame's fully qualified function name,
+// such as "example.com/pkg.(*T).Method", or a suffix of it starting after a
+// dot, such as "(*T).Method" or "Method".
+func AssertFrames(tb testing.TB, err error, wantFunctions ...string) {
+	tb.Helper()
+	var got []string
+	for _, f := range errors.StackTrace(err) {
+		function, _, _ := f.Location()
+		got = append(got, function)
+	}
+	i := 0
+	for _, fn := range got {
+		if i < len(wantFunctions) && matchFunction(fn, wantFunctions[i]) {
+			i++
+		}
+	}
+	if i < len(wantFunctions) {
+		tb.Errorf("frames of %v: missing %s (want %q in order); got:\n\t%s",
+			err, wantFunctions[i], wantFunctions, strings.Join(got, "\n\t"))
+	}
+}
+
+func matchFunction(function, name string) bool {
+	return function == name || strings.HasSuffix(function, "."+name)
+}
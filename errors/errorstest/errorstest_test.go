@@ -0,0 +1,49 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errorstest_test
+
+import (
+	"testing"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/errorstest"
+	"golang.org/x/exp/errors/fmt"
+)
+
+// recorder is a testing.TB that records failures instead of reporting them.
+type recorder struct {
+	testing.TB
+	failed bool
+}
+
+func (r *recorder) Helper()                       {}
+func (r *recorder) Errorf(string, ...interface{}) { r.failed = true }
+
+func inner() error { return errors.New("inner") }
+
+func outer() error { return fmt.Errorf("outer: %v", inner()) }
+
+func TestAssertFrames(t *testing.T) {
+	err := outer()
+	testCases := []struct {
+		want []string
+		fail bool
+	}{
+		{nil, false},
+		{[]string{"outer"}, false},
+		{[]string{"outer", "inner"}, false},
+		{[]string{"golang.org/x/exp/errors/errorstest_test.inner"}, false},
+		{[]string{"inner", "outer"}, true},
+		{[]string{"nner"}, true},
+		{[]string{"missing"}, true},
+	}
+	for _, tc := range testCases {
+		r := &recorder{TB: t}
+		errorstest.AssertFrames(r, err, tc.want...)
+		if r.failed != tc.fail {
+			t.Errorf("AssertFrames(%q): failed = %v, want %v", tc.want, r.failed, tc.fail)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package exec inspects the errors of commands run with package os/exec that
+// are wrapped with package golang.org/x/exp/errors. It is separate from that
+// package so that the latter does not depend on os/exec.
+package exec
+
+import (
+	"os/exec"
+
+	"golang.org/x/exp/errors"
+)
+
+// ExitCode returns the exit code of the first *exec.ExitError in err's
+// chain, as found by errors.As, and reports whether there is one. The exit
+// code is -1 if the process was terminated by a signal.
+func ExitCode(err error) (code int, ok bool) {
+	var e *exec.ExitError
+	if !errors.As(err, &e) {
+		return 0, false
+	}
+	return e.ExitCode(), true
+}
+
+// IsExitCode reports whether err's chain has an *exec.ExitError, as found by
+// ExitCode, for a process that exited with the given code.
+func IsExitCode(err error, code int) bool {
+	c, ok := ExitCode(err)
+	return ok && c == code
+}
@@ -0,0 +1,46 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package exec_test
+
+import (
+	"io"
+	osexec "os/exec"
+	"testing"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/exec"
+	"golang.org/x/exp/errors/fmt"
+)
+
+func TestExitCode(t *testing.T) {
+	sh, err := osexec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not found")
+	}
+	runErr := osexec.Command(sh, "-c", "exit 3").Run()
+	testCases := []struct {
+		err  error
+		code int
+		ok   bool
+	}{
+		{nil, 0, false},
+		{io.EOF, 0, false},
+		{runErr, 3, true},
+		{errors.WithStatus(fmt.Errorf("run: %v", runErr), 502), 3, true},
+		{errors.Join(io.EOF, runErr), 3, true},
+	}
+	for _, tc := range testCases {
+		code, ok := exec.ExitCode(tc.err)
+		if code != tc.code || ok != tc.ok {
+			t.Errorf("ExitCode(%v) = %d, %v; want %d, %v", tc.err, code, ok, tc.code, tc.ok)
+		}
+		if got := exec.IsExitCode(tc.err, 3); got != tc.ok {
+			t.Errorf("IsExitCode(%v, 3) = %v, want %v", tc.err, got, tc.ok)
+		}
+	}
+	if exec.IsExitCode(runErr, 0) {
+		t.Errorf("IsExitCode(%v, 0) = true, want false", runErr)
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+)
+
+// fingerprintFrames reports whether Fingerprint includes frame locations.
+var fingerprintFrames = true
+
+// SetFingerprintFrames sets whether Fingerprint includes the locations of
+// the frames recorded by the errors of a chain. By default it does, so that
+// errors with the same messages created at different places have different
+// fingerprints. Excluding them keeps the fingerprints of the same errors
+// stable across versions of a program in which their lines move.
+//
+// SetFingerprintFrames should be called early, for instance at the start of
+// main, and not concurrently with Fingerprint.
+func SetFingerprintFrames(include bool) {
+	fingerprintFrames = include
+}
+
+// Fingerprint returns a hash of err's tree, for use as a comparable key that
+// identifies the error across processes, such as to index or deduplicate
+// errors sent by several processes. It returns the zero value if err is nil.
+//
+// The hash covers, for each error visited by Walk, except the errors hidden by
+// Redact, in order:
+//
+//   - the message the error contributes, which for a Formatter is the
+//     message it prints and for any other error is its Error text;
+//   - the function and line of its frame, if it implements Framer, frames are
+//     included as set with SetFingerprintFrames, and its frame is not zero;
+//   - the number of errors it wraps, if it wraps several errors.
+//
+// The types and identities of the errors, their detail other than frames,
+// and the files of the frames are not included, so that errors decoded by
+// UnmarshalBinary have different fingerprints from the original ones, but
+// builds of a program in different directories do not. The hash is the
+// first 16 bytes of a SHA-256 digest and is the same in all processes and
+// versions of this package.
+func Fingerprint(err error) [16]byte {
+	var sum [16]byte
+	if err == nil {
+		return sum
+	}
+	h := sha256.New()
+	Walk(err, func(err error) bool {
+		writeString(h, message(err))
+		if f, ok := err.(Framer); ok && fingerprintFrames {
+			if function, _, line := f.Frame().Location(); function != "" || line != 0 {
+				h.Write([]byte{'f'})
+				writeString(h, function)
+				writeUvarint(h, uint64(line))
+			}
+		}
+		if u, ok := err.(interface{ Unwrap() []error }); ok {
+			h.Write([]byte{'n'})
+			writeUvarint(h, uint64(len(u.Unwrap())))
+		}
+		// The frame and count are tagged and the error terminated, so
+		// that either is not mistaken for the message of the next error.
+		h.Write([]byte{0})
+		return !isRedacted(err)
+	})
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
+
+// SameFingerprint reports whether x and y have the same fingerprint, as
+// returned by Fingerprint.
+func SameFingerprint(x, y error) bool {
+	return Fingerprint(x) == Fingerprint(y)
+}
+
+// writeString writes s to h, preceded by its length.
+func writeString(h hash.Hash, s string) {
+	writeUvarint(h, uint64(len(s)))
+	h.Write([]byte(s))
+}
+
+func writeUvarint(h hash.Hash, x uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], x)
+	h.Write(buf[:n])
+}
+
+// EquivalentIgnoringFrames reports whether x and y have trees of the same
+// shape made of errors with the same messages, regardless of the frames
+// recorded by these errors, so as to recognize the same failure in two
+// versions of a program in which the lines of the code moved, or in two runs
+// of a program. Two errors are equivalent if both are nil, or if
+//
+//   - they contribute the same message, as described for Fingerprint, and
+//   - they wrap several errors, as many for both, that are pairwise
+//     equivalent in order, or they both wrap a single equivalent error, as
+//     returned by Unwrap, or neither wraps an error.
+//
+// The types and identities of the errors are not compared, nor their detail.
+// The errors hidden by Opaque are not compared either. To stop on cycles, an
+// error whose dynamic type is a pointer is only compared the first time it is
+// reached, and the errors reached again at the same places of both trees are
+// equivalent.
+func EquivalentIgnoringFrames(x, y error) bool {
+	var wx, wy walker
+	return equivalent(&wx, &wy, x, y)
+}
+
+func equivalent(wx, wy *walker, x, y error) bool {
+	for {
+		if x == nil || y == nil {
+			return x == y
+		}
+		if vx, vy := wx.visited(x), wy.visited(y); vx || vy {
+			return vx == vy
+		}
+		if message(x) != message(y) {
+			return false
+		}
+		ux, xmulti := x.(interface{ Unwrap() []error })
+		uy, ymulti := y.(interface{ Unwrap() []error })
+		if xmulti || ymulti {
+			if !xmulti || !ymulti {
+				return false
+			}
+			xs, ys := ux.Unwrap(), uy.Unwrap()
+			if len(xs) != len(ys) {
+				return false
+			}
+			for i := range xs {
+				if !equivalent(wx, wy, xs[i], ys[i]) {
+					return false
+				}
+			}
+			return true
+		}
+		x, y = Unwrap(x), Unwrap(y)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors_test
+
+import (
+	"io"
+	"testing"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/fmt"
+)
+
+func TestFingerprint(t *testing.T) {
+	newErr := func(msg string) error { return fmt.Errorf("%s: %v", msg, io.EOF) }
+	a, b := newErr("read"), newErr("read")
+	if !errors.SameFingerprint(a, b) {
+		t.Errorf("SameFingerprint(%v, %v) = false, want true", a, b)
+	}
+	here := fmt.Errorf("read: %v", io.EOF)
+	testCases := []error{
+		newErr("write"),
+		here,
+		errors.Join(a),
+		errors.Join(a, io.EOF),
+		errors.WithStatus(a, 500),
+		io.EOF,
+	}
+	seen := map[[16]byte]error{errors.Fingerprint(a): a}
+	for _, err := range testCases {
+		fp := errors.Fingerprint(err)
+		if prev, ok := seen[fp]; ok {
+			t.Errorf("Fingerprint(%+v) = Fingerprint(%+v)", err, prev)
+		}
+		seen[fp] = err
+	}
+	if got := errors.Fingerprint(nil); got != ([16]byte{}) {
+		t.Errorf("Fingerprint(nil) = %x, want zero", got)
+	}
+
+	defer errors.SetFingerprintFrames(true)
+	errors.SetFingerprintFrames(false)
+	if !errors.SameFingerprint(a, here) {
+		t.Errorf("without frames: SameFingerprint(%v, %v) = false, want true", a, here)
+	}
+	if errors.SameFingerprint(a, newErr("write")) {
+		t.Errorf("without frames: SameFingerprint of different messages = true, want false")
+	}
+}
+
+func TestEquivalentIgnoringFrames(t *testing.T) {
+	newErr := func() error { return fmt.Errorf("read: %v", io.EOF) }
+	a, b := newErr(), fmt.Errorf("read: %v", io.EOF)
+	cycle1 := &cyclic{msg: "cycle"}
+	cycle1.next = cycle1
+	cycle2 := &cyclic{msg: "cycle"}
+	cycle2.next = cycle2
+	testCases := []struct {
+		x, y error
+		want bool
+	}{
+		{nil, nil, true},
+		{a, nil, false},
+		{a, b, true},
+		{errors.New("x"), errors.New("x"), true},
+		{errors.Join(a, io.EOF), errors.Join(b, io.EOF), true},
+		{errors.WithStatus(a, 404), b, false},
+		{cycle1, cycle2, true},
+		{a, fmt.Errorf("read: %v", io.ErrUnexpectedEOF), false},
+		{a, fmt.Errorf("write: %v", io.EOF), false},
+		{a, errors.New("read: EOF"), false},
+		{errors.Join(a, io.EOF), errors.Join(io.EOF, b), false},
+		{errors.Join(a), errors.Join(a, io.EOF), false},
+		{cycle1, &cyclic{msg: "cycle", next: cycle2}, false},
+	}
+	for _, tc := range testCases {
+		if got := errors.EquivalentIgnoringFrames(tc.x, tc.y); got != tc.want {
+			t.Errorf("EquivalentIgnoringFrames(%v, %v) = %v, want %v", tc.x, tc.y, got, tc.want)
+		}
+		if got := errors.EquivalentIgnoringFrames(tc.y, tc.x); got != tc.want {
+			t.Errorf("EquivalentIgnoringFrames(%v, %v) = %v, want %v", tc.y, tc.x, got, tc.want)
+		}
+	}
+}
@@ -5,19 +5,29 @@
 package fmt
 
 import (
-	"bytes"
+	"sort"
 	"strings"
 
 	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/internal"
 )
 
-// fmtError formats err according to verb, writing to p.
-// If it cannot handle the error, it does no formatting
-// and returns false.
-func errorf(format string, a []interface{}) error {
+func init() {
+	internal.Sprint = Sprint
+	internal.Sprintf = Sprintf
+}
+
+// sampledCaller is like errors.Caller, but returns a zero Frame unless the
+// frame is sampled according to the rate set with errors.SetFrameSampleRate.
+func sampledCaller(skip int) errors.Frame {
+	return internal.SampledCaller(errors.Caller, skip+1)
+}
+
+// errorf implements Errorf, recording frame as the location of the error.
+func errorf(frame errors.Frame, format string, a []interface{}) error {
 	err := lastError(format, a)
 	if err == nil {
-		return &simpleErr{Sprintf(format, a...), errors.Caller(2)}
+		return internal.Created(wrapErrorf(frame, format, a))
 	}
 
 	// TODO: this is not entirely correct. The error value could be
@@ -25,16 +35,129 @@ func errorf(format string, a []interface{}) error {
 	// substitutions. With relatively small changes to doPrintf we can
 	// have it optionally ignore extra arguments and pass the argument
 	// list in its entirety.
+	tmpl := template(format)
 	format = format[:len(format)-len(": %s")]
-	return &withChain{
-		msg:   Sprintf(format, a[:len(a)-1]...),
-		err:   err,
-		frame: errors.Caller(2),
+	return internal.Created(&withChain{
+		msg:      Sprintf(format, a[:len(a)-1]...),
+		err:      err,
+		frame:    frame,
+		id:       internal.NewID(),
+		depth:    wrapDepth(err),
+		plain:    internal.PlainMatch(err),
+		template: tmpl,
+	})
+}
+
+// wrapErrorf implements Errorf for format strings that do not end with an
+// error operand, wrapping the operands of %w verbs, if any.
+func wrapErrorf(frame errors.Frame, format string, a []interface{}) error {
+	p := newPrinter()
+	p.wrapErrs = true
+	p.doPrintf(format, a)
+	msg := string(p.buf)
+	if p.reordered {
+		sort.Ints(p.wrappedErrs)
 	}
+	var errs []error
+	for i, argNum := range p.wrappedErrs {
+		if i > 0 && p.wrappedErrs[i-1] == argNum {
+			continue
+		}
+		if err, ok := a[argNum].(error); ok {
+			errs = append(errs, err)
+		}
+	}
+	p.free()
+
+	switch len(errs) {
+	case 0:
+		return &simpleErr{msg, frame, internal.NewID(), template(format)}
+	case 1:
+		return &wrapError{msg, errs[0], frame, internal.NewID(), wrapDepth(errs[0]), internal.PlainMatch(errs[0]), template(format)}
+	}
+	return &wrapErrors{msg, errs, frame, internal.NewID(), wrapDepth(errs...), internal.PlainMatch(errs...), template(format)}
+}
+
+// template returns format if templates are stored, as set by
+// errors.SetStoreTemplates, and "" otherwise.
+func template(format string) string {
+	if !internal.StoreTemplates {
+		return ""
+	}
+	return format
+}
+
+// wrapf returns an error wrapping err with a message formatted according to
+// format. It must be called directly from the exported function whose caller
+// is recorded as the frame.
+func wrapf(err error, format string, a []interface{}) error {
+	return internal.Created(&withChain{
+		msg:      Sprintf(format, a...),
+		err:      err,
+		frame:    sampledCaller(2),
+		id:       internal.NewID(),
+		depth:    wrapDepth(err),
+		plain:    internal.PlainMatch(err),
+		template: template(format),
+	})
+}
+
+// rewrapf implements Rewrap. It must be called directly from Rewrap, whose
+// caller is recorded as the frame of a new link.
+func rewrapf(err error, format string, a []interface{}) error {
+	var next error
+	var frame errors.Frame
+	var id string
+	switch e := err.(type) {
+	case *withChain:
+		next, frame, id = e.err, e.frame, e.id
+	case *wrapError:
+		next, frame, id = e.err, e.frame, e.id
+	default:
+		return internal.Created(&withChain{
+			msg:      Sprintf(format, a...),
+			err:      err,
+			frame:    sampledCaller(2),
+			id:       internal.NewID(),
+			depth:    wrapDepth(err),
+			plain:    internal.PlainMatch(err),
+			template: template(format),
+		})
+	}
+	return internal.Created(&withChain{
+		msg:      Sprintf(format, a...),
+		err:      next,
+		frame:    frame,
+		id:       id,
+		depth:    wrapDepth(next),
+		plain:    internal.PlainMatch(next),
+		template: template(format),
+	})
+}
+
+// wrapDepth returns the depth of a chain wrapping errs and reports it to
+// the hook set by errors.SetChainWarnThreshold.
+func wrapDepth(errs ...error) int {
+	max := 0
+	for _, err := range errs {
+		if d := errors.Depth(err); d > max {
+			max = d
+		}
+	}
+	internal.CheckDepth(max + 1)
+	return max + 1
+}
+
+// hasWrapVerb reports whether format may contain a %w verb.
+func hasWrapVerb(format string) bool {
+	return strings.Contains(format, "%w") || strings.Contains(format, "]w")
 }
 
 func lastError(format string, a []interface{}) error {
-	if !strings.HasSuffix(format, ": %s") && !strings.HasSuffix(format, ": %v") {
+	switch {
+	case strings.HasSuffix(format, ": %s"), strings.HasSuffix(format, ": %v"):
+	case strings.HasSuffix(format, ": %w") && !hasWrapVerb(format[:len(format)-len(": %w")]):
+	default:
 		return nil
 	}
 
@@ -51,8 +174,10 @@ func lastError(format string, a []interface{}) error {
 }
 
 type simpleErr struct {
-	msg   string
-	frame errors.Frame
+	msg      string
+	frame    errors.Frame
+	id       string
+	template string
 }
 
 func (e *simpleErr) Error() string {
@@ -62,14 +187,49 @@ func (e *simpleErr) Error() string {
 func (e *simpleErr) Format(p errors.Printer) (next error) {
 	p.Print(e.msg)
 	e.frame.Format(p)
+	internal.FormatID(p, e.id)
 	return nil
 }
 
+func (e *simpleErr) Is(target error) bool {
+	t, ok := target.(*simpleErr)
+	return ok && internal.SentinelMatchByValue && t.msg == e.msg
+}
+
+func (e *simpleErr) PlainMatch() bool { return true }
+
+func (e *simpleErr) Frame() errors.Frame {
+	return e.frame
+}
+
+func (e *simpleErr) Reframe(frame errors.Frame) error {
+	c := *e
+	c.frame = frame
+	return &c
+}
+
+func (e *simpleErr) Depth() int {
+	return 1
+}
+
+func (e *simpleErr) ID() string {
+	return e.id
+}
+
+func (e *simpleErr) Template() string {
+	return e.template
+}
+
 type withChain struct {
 	// TODO: add frame information
 	msg   string
 	err   error
 	frame errors.Frame
+	id    string
+	depth int  // number of errors in the chain, including this one
+	plain bool // whether the chain only matches by equality
+
+	template string // format of the message, if stored
 }
 
 func (e *withChain) Error() string {
@@ -79,18 +239,161 @@ func (e *withChain) Error() string {
 func (e *withChain) Format(p errors.Printer) (next error) {
 	p.Print(e.msg)
 	e.frame.Format(p)
+	internal.FormatID(p, e.id)
 	return e.err
 }
 
+func (e *withChain) PlainMatch() bool { return e.plain }
+
+func (e *withChain) Frame() errors.Frame {
+	return e.frame
+}
+
+func (e *withChain) Reframe(frame errors.Frame) error {
+	c := *e
+	c.frame = frame
+	return &c
+}
+
+func (e *withChain) Depth() int {
+	return e.depth
+}
+
+func (e *withChain) ID() string {
+	return e.id
+}
+
+func (e *withChain) Template() string {
+	return e.template
+}
+
 func (e *withChain) Unwrap() error {
 	return e.err
 }
 
+func (e *withChain) CloneWrapping(errs []error) error {
+	c := *e
+	c.err = errs[0]
+	return &c
+}
+
+// wrapError is an error created by Errorf with a single %w verb.
+// Its message includes the text of the wrapped error.
+type wrapError struct {
+	msg   string
+	err   error
+	frame errors.Frame
+	id    string
+	depth int
+	plain bool
+
+	template string
+}
+
+func (e *wrapError) Error() string {
+	return Sprint(e)
+}
+
+func (e *wrapError) Format(p errors.Printer) (next error) {
+	p.Print(e.msg)
+	e.frame.Format(p)
+	internal.FormatID(p, e.id)
+	return nil
+}
+
+func (e *wrapError) PlainMatch() bool { return e.plain }
+
+func (e *wrapError) Frame() errors.Frame {
+	return e.frame
+}
+
+func (e *wrapError) Reframe(frame errors.Frame) error {
+	c := *e
+	c.frame = frame
+	return &c
+}
+
+func (e *wrapError) Depth() int {
+	return e.depth
+}
+
+func (e *wrapError) ID() string {
+	return e.id
+}
+
+func (e *wrapError) Template() string {
+	return e.template
+}
+
+func (e *wrapError) Unwrap() error {
+	return e.err
+}
+
+func (e *wrapError) CloneWrapping(errs []error) error {
+	c := *e
+	c.err = errs[0]
+	return &c
+}
+
+// wrapErrors is an error created by Errorf with several %w verbs.
+// Its message includes the text of the wrapped errors.
+type wrapErrors struct {
+	msg   string
+	errs  []error
+	frame errors.Frame
+	id    string
+	depth int
+	plain bool
+
+	template string
+}
+
+func (e *wrapErrors) Error() string {
+	return Sprint(e)
+}
+
+func (e *wrapErrors) Format(p errors.Printer) (next error) {
+	p.Print(e.msg)
+	e.frame.Format(p)
+	internal.FormatID(p, e.id)
+	return nil
+}
+
+func (e *wrapErrors) PlainMatch() bool { return e.plain }
+
+func (e *wrapErrors) Frame() errors.Frame {
+	return e.frame
+}
+
+func (e *wrapErrors) Reframe(frame errors.Frame) error {
+	c := *e
+	c.frame = frame
+	return &c
+}
+
+func (e *wrapErrors) Depth() int {
+	return e.depth
+}
+
+func (e *wrapErrors) ID() string {
+	return e.id
+}
+
+func (e *wrapErrors) Template() string {
+	return e.template
+}
+
+func (e *wrapErrors) Unwrap() []error {
+	return e.errs
+}
+
+func (e *wrapErrors) CloneWrapping(errs []error) error {
+	c := *e
+	c.errs = errs
+	return &c
+}
+
 func fmtError(p *pp, verb rune, err error) (handled bool) {
-	var (
-		sep = " " // separator before next error
-		w   = p   // print buffer where error text is written
-	)
 	switch {
 	// Note that this switch must match the preference order
 	// for ordinary string printing (%#v before %+v, and so on).
@@ -104,132 +407,18 @@ func fmtError(p *pp, verb rune, err error) (handled bool) {
 		return false
 
 	case p.fmt.plusV:
-		sep = "\n--- "
-		w.fmt.fmtFlags = fmtFlags{plusV: p.fmt.plusV} // only keep detail flag
-
+		// Don't use formatting flags for the detailed view.
 		// The width or precision of a detailed view could be the number of
 		// errors to print from a list.
+		p.buf.WriteString(errors.Format(err, true))
 
 	default:
-		// Use an intermediate buffer in the rare cases that precision,
-		// truncation, or one of the alternative verbs (q, x, and X) are
-		// specified.
 		switch verb {
-		case 's', 'v':
-			if (!w.fmt.widPresent || w.fmt.wid == 0) && !w.fmt.precPresent {
-				break
-			}
-			fallthrough
-		case 'q', 'x', 'X':
-			w = newPrinter()
-			defer w.free()
-		default:
-			w.badVerb(verb)
-			return true
-		}
-	}
-
-loop:
-	for {
-		w.fmt.inDetail = false
-		switch v := err.(type) {
-		case errors.Formatter:
-			err = v.Format((*errPP)(w))
-		// TODO: This case is for supporting old error implementations.
-		// It may eventually disappear.
-		case interface{ FormatError(errors.Printer) error }:
-			err = v.FormatError((*errPP)(w))
-		case Formatter:
-			// Discard verb, but keep the flags. Discarding the verb prevents
-			// nested quoting and other unwanted behavior. Preserving flags
-			// recursively signals a request for detail, if interpreted as %+v.
-			w.fmt.fmtFlags = p.fmt.fmtFlags
-			if w.fmt.plusV {
-				v.Format((*errPPState)(w), 'v') // indent new lines
-			} else {
-				v.Format(w, 'v') // do not indent new lines
-			}
-			break loop
+		case 's', 'v', 'q', 'x', 'X':
+			p.fmtString(errors.Format(err, false), verb)
 		default:
-			w.fmtString(v.Error(), 's')
-			break loop
-		}
-		if err == nil {
-			break
-		}
-		if !w.fmt.inDetail || !p.fmt.plusV {
-			w.buf.WriteByte(':')
+			p.badVerb(verb)
 		}
-		// Strip last newline of detail.
-		if bytes.HasSuffix([]byte(w.buf), detailSep) {
-			w.buf = w.buf[:len(w.buf)-len(detailSep)]
-		}
-		w.buf.WriteString(sep)
-		w.fmt.inDetail = false
-	}
-
-	if w != p {
-		p.fmtString(string(w.buf), verb)
 	}
 	return true
 }
-
-var detailSep = []byte("\n    ")
-
-// errPPState wraps a pp to implement State with indentation. It is used
-// for errors implementing fmt.Formatter.
-type errPPState pp
-
-func (p *errPPState) Width() (wid int, ok bool)      { return (*pp)(p).Width() }
-func (p *errPPState) Precision() (prec int, ok bool) { return (*pp)(p).Precision() }
-func (p *errPPState) Flag(c int) bool                { return (*pp)(p).Flag(c) }
-
-func (p *errPPState) Write(b []byte) (n int, err error) {
-	if !p.fmt.inDetail || p.fmt.plusV {
-		k := 0
-		if p.fmt.indent {
-			for i, c := range b {
-				if c == '\n' {
-					p.buf.Write(b[k:i])
-					p.buf.Write(detailSep)
-					k = i + 1
-				}
-			}
-		}
-		p.buf.Write(b[k:])
-	}
-	return len(b), nil
-}
-
-// errPP wraps a pp to implement an errors.Printer.
-type errPP pp
-
-func (p *errPP) Print(args ...interface{}) {
-	if !p.fmt.inDetail || p.fmt.plusV {
-		if p.fmt.indent {
-			Fprint((*errPPState)(p), args...)
-		} else {
-			(*pp)(p).doPrint(args)
-		}
-	}
-}
-
-func (p *errPP) Printf(format string, args ...interface{}) {
-	if !p.fmt.inDetail || p.fmt.plusV {
-		if p.fmt.indent {
-			Fprintf((*errPPState)(p), format, args...)
-		} else {
-			(*pp)(p).doPrintf(format, args)
-		}
-	}
-}
-
-func (p *errPP) Detail() bool {
-	inDetail := p.fmt.inDetail
-	p.fmt.inDetail = true
-	p.fmt.indent = p.fmt.plusV
-	if p.fmt.plusV && !inDetail {
-		(*errPPState)(p).Write([]byte(":\n"))
-	}
-	return p.fmt.plusV
-}
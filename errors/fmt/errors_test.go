@@ -20,12 +20,6 @@ import (
 
 func TestErrorf(t *testing.T) {
 	chained := &wrapped{"chained", nil}
-	chain := func(s ...string) (a []string) {
-		for _, s := range s {
-			a = append(a, cleanPath(s))
-		}
-		return a
-	}
 	testCases := []struct {
 		got  error
 		want []string
@@ -67,6 +61,127 @@ func TestErrorf(t *testing.T) {
 	}
 }
 
+func TestErrorfWrap(t *testing.T) {
+	errA := errors.New("a")
+	errB := &wrapped{"b", nil}
+
+	testCases := []struct {
+		err    error
+		msg    string
+		unwrap []error
+	}{{
+		fmt.Errorf("%w: %w", errA, errB),
+		"a: b",
+		[]error{errA, errB},
+	}, {
+		fmt.Errorf("%[2]w, %[1]w and %[2]w", errA, errB),
+		"b, a and b",
+		[]error{errA, errB},
+	}, {
+		fmt.Errorf("read %w failed", errA),
+		"read a failed",
+		[]error{errA},
+	}, {
+		fmt.Errorf("ctx: %w", errA),
+		"ctx: a",
+		[]error{errA},
+	}, {
+		fmt.Errorf("%w (%w)", errA, 1),
+		"a (%!w(int=1))",
+		[]error{errA},
+	}, {
+		fmt.Errorf("100%%w"),
+		"100%w",
+		nil,
+	}}
+	for _, tc := range testCases {
+		if got := tc.err.Error(); got != tc.msg {
+			t.Errorf("Error() = %q, want %q", got, tc.msg)
+		}
+		if got := fmt.Sprint(tc.err); got != tc.msg {
+			t.Errorf("Sprint = %q, want %q", got, tc.msg)
+		}
+		var got []error
+		switch u := tc.err.(type) {
+		case interface{ Unwrap() []error }:
+			got = u.Unwrap()
+		case errors.Wrapper:
+			got = []error{u.Unwrap()}
+		}
+		if !reflect.DeepEqual(got, tc.unwrap) {
+			t.Errorf("%q: unwrapped %v, want %v", tc.msg, got, tc.unwrap)
+		}
+		for _, target := range tc.unwrap {
+			if !errors.Is(tc.err, target) {
+				t.Errorf("Is(%q, %v) = false, want true", tc.msg, target)
+			}
+		}
+	}
+
+	if got := fmt.Sprintf("%w", errA); !strings.HasPrefix(got, "%!w(") {
+		t.Errorf("Sprintf(%%w) = %q, want %%!w(...)", got)
+	}
+
+	// A trailing ": %w" wraps the error as a chain.
+	want := chain("wraps:ctx/path.TestErrorfWrap/path.go:xxx", "a/path.TestErrorfWrap/path.go:xxx")
+	if parts := errToParts(fmt.Errorf("ctx: %w", errA)); !reflect.DeepEqual(parts, want) {
+		t.Errorf("Format:\n got: %#v\nwant: %#v", parts, want)
+	}
+}
+
+func TestWrapIf(t *testing.T) {
+	err1 := errors.New("1")
+	testCases := []struct {
+		cond bool
+		err  error
+		want error
+	}{
+		{false, nil, nil},
+		{true, nil, nil},
+		{false, err1, err1},
+	}
+	for _, tc := range testCases {
+		if got := fmt.WrapIf(tc.cond, tc.err, "ctx %d", 1); got != tc.want {
+			t.Errorf("WrapIf(%v, %v) = %v, want %v", tc.cond, tc.err, got, tc.want)
+		}
+	}
+
+	got := fmt.WrapIf(true, err1, "ctx %d", 1)
+	if got.Error() != "ctx 1: 1" {
+		t.Errorf("WrapIf(true, err1).Error() = %q, want %q", got.Error(), "ctx 1: 1")
+	}
+	if errors.Unwrap(got) != err1 {
+		t.Errorf("Unwrap(WrapIf(true, err1)) = %v, want %v", errors.Unwrap(got), err1)
+	}
+	want := chain("wraps:ctx 1/path.TestWrapIf/path.go:xxx", "1/path.TestWrapIf/path.go:xxx")
+	if parts := errToParts(got); !reflect.DeepEqual(parts, want) {
+		t.Errorf("Format:\n got: %#v\nwant: %#v", parts, want)
+	}
+}
+
+func registered() errors.Frame { return errors.Caller(1) }
+
+func TestErrorfAt(t *testing.T) {
+	frame := registered()
+	err := fmt.ErrorfAt(frame, "handler %d", 1)
+	if got, want := err.Error(), "handler 1"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if got := err.(errors.Framer).Frame(); got != frame {
+		t.Errorf("Frame() = %v, want %v", got, frame)
+	}
+	want := chain("handler 1/path.TestErrorfAt/path.go:xxx")
+	if parts := errToParts(err); !reflect.DeepEqual(parts, want) {
+		t.Errorf("Format:\n got: %#v\nwant: %#v", parts, want)
+	}
+
+	err1 := errors.New("1")
+	err = fmt.ErrorfAt(frame, "wrap: %v", err1)
+	if errors.Unwrap(err) != err1 || err.(errors.Framer).Frame() != frame {
+		t.Errorf("ErrorfAt(frame, \"wrap: %%v\", err1): does not wrap err1 at frame")
+	}
+}
+
 func TestErrorFormatter(t *testing.T) {
 	var (
 		simple   = &wrapped{"simple", nil}
@@ -375,6 +490,13 @@ func (panicValue) String() string { panic("panic") }
 var rePath = regexp.MustCompile(`( [^ ]*)fmt.*test\.`)
 var reLine = regexp.MustCompile(":[0-9]*\n?$")
 
+func chain(s ...string) (a []string) {
+	for _, s := range s {
+		a = append(a, cleanPath(s))
+	}
+	return a
+}
+
 func cleanPath(s string) string {
 	s = rePath.ReplaceAllString(s, "/path.")
 	s = reLine.ReplaceAllString(s, ":xxx")
@@ -417,3 +539,43 @@ func (p *testPrinter) Detail() bool {
 	p.str += " /"
 	return true
 }
+
+func TestRewrap(t *testing.T) {
+	err1 := errors.New("1")
+	if err := fmt.Rewrap(nil, "ctx"); err != nil {
+		t.Errorf("Rewrap(nil) = %v, want nil", err)
+	}
+
+	testCases := []struct {
+		err  error
+		want string
+	}{
+		{fmt.Errorf("old: %v", err1), "new 2: 1"},
+		{fmt.Errorf("old %w", err1), "new 2: 1"},
+		{err1, "new 2: 1"},
+		{fmt.Errorf("old: %w and %w", err1, io.EOF), "new 2: old: 1 and EOF"},
+	}
+	for _, tc := range testCases {
+		got := fmt.Rewrap(tc.err, "new %d", 2)
+		if got.Error() != tc.want {
+			t.Errorf("Rewrap(%v) = %q, want %q", tc.err, got.Error(), tc.want)
+		}
+		if !errors.Is(got, err1) {
+			t.Errorf("Is(Rewrap(%v), err1) = false, want true", tc.err)
+		}
+	}
+
+	// The wrapper is replaced, keeping its location.
+	old := fmt.Errorf("old: %v", err1)
+	got := fmt.Rewrap(old, "new")
+	if errors.Unwrap(got) != err1 {
+		t.Errorf("Unwrap(Rewrap(old)) = %v, want err1", errors.Unwrap(got))
+	}
+	if got.(errors.Framer).Frame() != old.(errors.Framer).Frame() {
+		t.Errorf("Rewrap(old) does not keep the frame of old")
+	}
+	// Other errors are wrapped.
+	if got := fmt.Rewrap(err1, "new"); errors.Unwrap(got) != err1 {
+		t.Errorf("Unwrap(Rewrap(err1)) = %v, want err1", errors.Unwrap(got))
+	}
+}
@@ -5,11 +5,14 @@
 package fmt
 
 import (
+	stdfmt "fmt"
 	"io"
 	"os"
 	"reflect"
 	"sync"
 	"unicode/utf8"
+
+	"golang.org/x/exp/errors"
 )
 
 // Strings for use with buffer.WriteString.
@@ -34,24 +37,17 @@ const (
 // State represents the printer state passed to custom formatters.
 // It provides access to the io.Writer interface plus information about
 // the flags and options for the operand's format specifier.
-type State interface {
-	// Write is the function to call to emit formatted output to be printed.
-	Write(b []byte) (n int, err error)
-	// Width returns the value of the width option and whether it has been set.
-	Width() (wid int, ok bool)
-	// Precision returns the value of the precision option and whether it has been set.
-	Precision() (prec int, ok bool)
-
-	// Flag reports whether the flag c, a character, has been set.
-	Flag(c int) bool
-}
+//
+// State is the State of the standard fmt package, so that values can
+// implement Formatter for both packages at once.
+type State = stdfmt.State
 
 // Formatter is the interface implemented by values with a custom formatter.
 // The implementation of Format may call Sprint(f) or Fprint(f) etc.
 // to generate its output.
-type Formatter interface {
-	Format(f State, c rune)
-}
+//
+// Formatter is the Formatter of the standard fmt package.
+type Formatter = stdfmt.Formatter
 
 // Stringer is implemented by any value that has a String method,
 // which defines the ``native'' format for that value.
@@ -121,6 +117,10 @@ type pp struct {
 	panicking bool
 	// erroring is set when printing an error string to guard against calling handleMethods.
 	erroring bool
+	// wrapErrs is set when the format string may contain a %w verb.
+	wrapErrs bool
+	// wrappedErrs records the arguments of the %w verbs.
+	wrappedErrs []int
 }
 
 var ppFree = sync.Pool{
@@ -132,6 +132,7 @@ func newPrinter() *pp {
 	p := ppFree.Get().(*pp)
 	p.panicking = false
 	p.erroring = false
+	p.wrapErrs = false
 	p.fmt.init(&p.buf)
 	return p
 }
@@ -141,6 +142,7 @@ func (p *pp) free() {
 	p.buf = p.buf[:0]
 	p.arg = nil
 	p.value = reflect.Value{}
+	p.wrappedErrs = p.wrappedErrs[:0]
 	ppFree.Put(p)
 }
 
@@ -210,8 +212,62 @@ func Sprintf(format string, a ...interface{}) string {
 //
 // The returned error includes the file and line number of the caller
 // when formatted with additional detail enabled.
+//
+// If the format specifier ends with ": %s" or ": %v" (or ": %w", if it
+// contains no other %w verb) and the last argument is an error, the returned
+// error prints its own message followed by that error and unwraps to it.
+// Otherwise, if the format specifier includes %w verbs with error operands,
+// the message includes the text of these errors, formatted as by %v, and
+// the returned error has an Unwrap method returning the error if there is
+// a single one, or a method
+//
+//	Unwrap() []error
+//
+// returning the errors in argument order if there are several.
+// It is invalid to supply the %w verb with an operand that does not
+// implement the error interface.
 func Errorf(format string, a ...interface{}) error {
-	return errorf(format, a)
+	return errorf(sampledCaller(1), format, a)
+}
+
+// ErrorfAt is like Errorf but records frame as the location of the returned
+// error instead of the location of its caller. It is meant for helpers that
+// create errors on behalf of code whose location they captured beforehand
+// with errors.Caller, such as the registration site of a handler.
+func ErrorfAt(frame errors.Frame, format string, a ...interface{}) error {
+	return errorf(frame, format, a)
+}
+
+// WrapIf returns an error wrapping err with a message formatted according to
+// a format specifier if cond is true and err is non-nil. Otherwise it returns
+// err unchanged.
+//
+// If it wraps, WrapIf(cond, err, format, a...) is equivalent to
+// Errorf(format+": %v", append(a, err)...). The returned error includes the
+// file and line number of the caller of WrapIf.
+func WrapIf(cond bool, err error, format string, a ...interface{}) error {
+	if !cond || err == nil {
+		return err
+	}
+	return wrapf(err, format, a)
+}
+
+// Rewrap returns an error like err with a message formatted according to a
+// format specifier in place of the message of err, for replacing the message
+// of a wrapper with a better one without lengthening the chain.
+//
+// If err was created by Errorf or WrapIf and wraps a single error, the
+// returned error wraps the same error and keeps the location and ID of err,
+// so that it prints as err would with the new message followed by ": " and
+// the wrapped error. Otherwise, Rewrap wraps err as WrapIf(true, err, format,
+// a...) does: the message of err is kept after the new one, and the returned
+// error includes the file and line number of the caller of Rewrap. Rewrap
+// returns nil if err is nil.
+func Rewrap(err error, format string, a ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	return rewrapf(err, format, a)
 }
 
 // These routines do not take a format string
@@ -565,6 +621,15 @@ func (p *pp) handleMethods(verb rune) (handled bool) {
 	if p.erroring {
 		return
 	}
+	if verb == 'w' {
+		// It is invalid to use %w other than with Errorf or with a non-error arg.
+		if _, ok := p.arg.(error); !ok || !p.wrapErrs {
+			p.badVerb(verb)
+			return true
+		}
+		// If the arg is a Formatter, pass 'v' as the verb to it.
+		verb = 'v'
+	}
 	// Is it a Formatter?
 	if formatter, ok := p.arg.(Formatter); ok {
 		handled = true
@@ -995,6 +1060,8 @@ formatLoop:
 						// Struct-field syntax
 						p.fmt.plusV = p.fmt.plus
 						p.fmt.plus = false
+					} else if c == 'w' {
+						p.wrappedErrs = append(p.wrappedErrs, argNum)
 					}
 					p.printArg(a[argNum], rune(c))
 					argNum++
@@ -1092,6 +1159,9 @@ formatLoop:
 			p.fmt.plus = false
 			fallthrough
 		default:
+			if verb == 'w' {
+				p.wrappedErrs = append(p.wrappedErrs, argNum)
+			}
 			p.printArg(a[argNum], verb)
 			argNum++
 		}
@@ -4,6 +4,8 @@
 
 package errors
 
+import "fmt"
+
 // A Formatter formats error messages.
 type Formatter interface {
 	// Format prints the receiver's first error and returns the next error in
@@ -30,3 +32,81 @@ type Printer interface {
 	// If Detail returns false, the caller can avoid printing the detail at all.
 	Detail() bool
 }
+
+// FormatErrorAdapter returns a Formatter for an error implemented before
+// Formatter replaced the method
+//
+//	FormatError(p Printer) (next error)
+//
+// which Format and the fmt verbs still recognize for now, but may eventually
+// stop supporting. The returned Formatter calls e.FormatError. It is also an
+// error whose text is the result of Format for it without detail and which
+// unwraps to the error that e wraps, if e is an error.
+//
+// To migrate, an error type can rename its FormatError method to Format, or,
+// where its method set cannot change yet, the errors can be wrapped with
+// FormatErrorAdapter where they are created or printed.
+func FormatErrorAdapter(e interface{ FormatError(Printer) error }) Formatter {
+	return formatErrorAdapter{e}
+}
+
+type formatErrorAdapter struct {
+	e interface{ FormatError(Printer) error }
+}
+
+func (a formatErrorAdapter) Error() string { return Format(a, false) }
+
+func (a formatErrorAdapter) Format(p Printer) (next error) {
+	return a.e.FormatError(p)
+}
+
+func (a formatErrorAdapter) Unwrap() error {
+	if err, ok := a.e.(error); ok {
+		return Unwrap(err)
+	}
+	return nil
+}
+
+// StateFrom returns a fmt.State that writes to p, so that renderers built
+// around a Printer can print errors that implement fmt.Formatter rather than
+// Formatter. If p implements fmt.State, as the Printers of this package do,
+// it is returned as is. Otherwise, the returned State prints what is written
+// to it with p.Print, and its Width, Precision and Flag methods call those of
+// p, if p has them, or report that no width, precision or flag is set.
+func StateFrom(p Printer) fmt.State {
+	if s, ok := p.(fmt.State); ok {
+		return s
+	}
+	return printerState{p}
+}
+
+// printerState adapts a Printer to fmt.State.
+type printerState struct {
+	p Printer
+}
+
+func (s printerState) Write(b []byte) (n int, err error) {
+	s.p.Print(string(b))
+	return len(b), nil
+}
+
+func (s printerState) Width() (wid int, ok bool) {
+	if w, ok := s.p.(interface{ Width() (int, bool) }); ok {
+		return w.Width()
+	}
+	return 0, false
+}
+
+func (s printerState) Precision() (prec int, ok bool) {
+	if w, ok := s.p.(interface{ Precision() (int, bool) }); ok {
+		return w.Precision()
+	}
+	return 0, false
+}
+
+func (s printerState) Flag(c int) bool {
+	if f, ok := s.p.(interface{ Flag(int) bool }); ok {
+		return f.Flag(c)
+	}
+	return false
+}
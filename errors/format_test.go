@@ -0,0 +1,249 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors_test
+
+import (
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/fmt"
+)
+
+func TestFormat(t *testing.T) {
+	err := fmt.Errorf("wrap: %v", errorD{})
+	testCases := []struct {
+		err    error
+		detail bool
+		want   string
+	}{
+		{nil, false, "<nil>"},
+		{errorT{}, false, "errorT"},
+		{errorT{}, true, "errorT"},
+		{errorD{}, false, "errorD"},
+		{errorD{}, true, "errorD:\n    detail"},
+		{err, false, "wrap: errorD"},
+		{errors.Opaque(errorD{}), true, "errorD:\n    detail"},
+	}
+	for _, tc := range testCases {
+		got := errors.Format(tc.err, tc.detail)
+		if got != tc.want {
+			t.Errorf("Format(%v, %v) = %q, want %q", tc.err, tc.detail, got, tc.want)
+		}
+	}
+}
+
+func TestFormatMatchesVerbs(t *testing.T) {
+	err := fmt.Errorf("outer: %v", fmt.Errorf("mid: %v", errors.New("inner")))
+	if got, want := errors.Format(err, false), fmt.Sprintf("%v", err); got != want {
+		t.Errorf("Format(err, false) = %q; %%v = %q", got, want)
+	}
+	if got, want := errors.Format(err, true), fmt.Sprintf("%+v", err); got != want {
+		t.Errorf("Format(err, true) = %q; %%+v = %q", got, want)
+	}
+}
+
+func TestSprint(t *testing.T) {
+	err := fmt.Errorf("wrap: %v", errorD{})
+	for _, detail := range []bool{false, true} {
+		verb := "%v"
+		if detail {
+			verb = "%+v"
+		}
+		want := fmt.Sprintf(verb, err)
+		if got := errors.Sprint(err, detail); got != want {
+			t.Errorf("Sprint(err, %v) = %q, want %q", detail, got, want)
+		}
+		if got := errors.Sprintln(err, detail); got != want+"\n" {
+			t.Errorf("Sprintln(err, %v) = %q, want %q", detail, got, want+"\n")
+		}
+	}
+}
+
+// stateErr implements fmt.Formatter, printing the flags and width it is given.
+type stateErr struct{}
+
+func (stateErr) Error() string { return "stateErr" }
+
+func (stateErr) Format(s fmt.State, verb rune) {
+	w, ok := s.Width()
+	fmt.Fprintf(s, "stateErr plus=%v width=%d,%v", s.Flag('+'), w, ok)
+}
+
+// textPrinter is a Printer that records the text printed to it.
+type textPrinter struct {
+	text string
+}
+
+func (p *textPrinter) Print(args ...interface{}) { p.text += fmt.Sprint(args...) }
+func (p *textPrinter) Printf(format string, args ...interface{}) {
+	p.text += fmt.Sprintf(format, args...)
+}
+func (p *textPrinter) Detail() bool { return false }
+
+// flagPrinter is a textPrinter that reports the plus flag and a width.
+type flagPrinter struct {
+	textPrinter
+}
+
+func (p *flagPrinter) Width() (int, bool) { return 8, true }
+func (p *flagPrinter) Flag(c int) bool    { return c == '+' }
+
+func TestStateFrom(t *testing.T) {
+	var tp textPrinter
+	stateErr{}.Format(errors.StateFrom(&tp), 'v')
+	if want := "stateErr plus=false width=0,false"; tp.text != want {
+		t.Errorf("textPrinter: got %q; want %q", tp.text, want)
+	}
+
+	var fp flagPrinter
+	stateErr{}.Format(errors.StateFrom(&fp), 'v')
+	if want := "stateErr plus=true width=8,true"; fp.text != want {
+		t.Errorf("flagPrinter: got %q; want %q", fp.text, want)
+	}
+
+	// The Printers of this package are used directly.
+	if got, want := errors.Format(fmt.Errorf("wrap: %v", stateErr{}), true), "plus=true"; !strings.Contains(got, want) {
+		t.Errorf("Format: got %q; want it to contain %q", got, want)
+	}
+}
+
+// oldFormatter implements the method that preceded Formatter.
+type oldFormatter struct{ next error }
+
+func (e oldFormatter) Error() string { return "old" }
+
+func (e oldFormatter) Unwrap() error { return e.next }
+
+func (e oldFormatter) FormatError(p errors.Printer) (next error) {
+	p.Print("old")
+	if p.Detail() {
+		p.Print("old detail")
+	}
+	return e.next
+}
+
+func TestFormatErrorAdapter(t *testing.T) {
+	f := errors.FormatErrorAdapter(oldFormatter{errorT{}})
+	err, ok := f.(error)
+	if !ok {
+		t.Fatalf("FormatErrorAdapter result is not an error")
+	}
+	if got, want := err.Error(), "old: errorT"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if got, want := errors.Format(err, true), "old:\n    old detail\n--- errorT"; got != want {
+		t.Errorf("Format(detail) = %q, want %q", got, want)
+	}
+	if !errors.Is(err, errorT{}) {
+		t.Errorf("Is(err, errorT{}) = false, want true")
+	}
+}
+
+func TestIndent(t *testing.T) {
+	defer errors.SetFrameSampleRate(1)
+	errors.SetFrameSampleRate(0)
+	testCases := []struct {
+		err  error
+		want string
+	}{
+		{errorT{}, "> errorT"},
+		{errorD{}, "> errorD:\n>     detail"},
+		{fmt.Errorf("wrap: %v", errors.WithRetryAfter(errorT{}, 0)), "> wrap:\n> --- errorT:\n>     retry after 0s"},
+		{errors.New("two\nlines"), "> two\n> lines"},
+	}
+	for _, tc := range testCases {
+		if got := errors.Indent(tc.err, "> "); got != tc.want {
+			t.Errorf("Indent(%v) = %q, want %q", tc.err, got, tc.want)
+		}
+	}
+}
+
+// plusErr implements fmt.Formatter, printing detail for %+v.
+type plusErr struct{}
+
+func (plusErr) Error() string { return "plusErr" }
+
+func (plusErr) Format(s fmt.State, verb rune) {
+	io.WriteString(s, "plusErr")
+	if s.Flag('+') {
+		io.WriteString(s, "\nplus detail")
+	}
+}
+
+func TestDetailString(t *testing.T) {
+	testCases := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{errorT{}, ""},
+		{errorD{}, "detail\n"},
+		{errors.WithRetryAfter(errorT{}, time.Second), "retry after 1s\n"},
+		{errors.Join(errorD{}, errorT{}), "detail\n"},
+		{plusErr{}, "plus detail\n"},
+		{stateErr{}, ""},
+		{errors.Join(errorT{}, errors.Join(errorD{}), errors.WithRetryAfter(errorT{}, time.Second)), "detail\nretry after 1s\n"},
+	}
+	for _, tc := range testCases {
+		if got := errors.DetailString(tc.err); got != tc.want {
+			t.Errorf("DetailString(%v) = %q, want %q", tc.err, got, tc.want)
+		}
+	}
+
+	err := fmt.Errorf("wrap: %v", errors.New("inner"))
+	re := regexp.MustCompile(`^golang.org/x/exp/errors_test.TestDetailString\n    .*format_test.go:\d+\n` +
+		`golang.org/x/exp/errors_test.TestDetailString\n    .*format_test.go:\d+\n$`)
+	if got := errors.DetailString(err); !re.MatchString(got) {
+		t.Errorf("DetailString(%v) = %q, want match for %s", err, got, re)
+	}
+
+	err = errors.Cached(fmt.Errorf("wrap: %v", errors.New("inner")))
+	if got := errors.DetailString(err); !re.MatchString(got) {
+		t.Errorf("DetailString(%v) = %q, want match for %s", err, got, re)
+	}
+
+	err = errors.Join(errors.New("e1"), errors.New("e2"))
+	if got := errors.DetailString(err); !re.MatchString(got) {
+		t.Errorf("DetailString(%v) = %q, want match for %s", err, got, re)
+	}
+}
+
+// failingWriter fails after n writes.
+type failingWriter struct {
+	n      int
+	writes []string
+}
+
+func (w *failingWriter) Write(b []byte) (int, error) {
+	if len(w.writes) == w.n {
+		return 0, io.ErrShortWrite
+	}
+	w.writes = append(w.writes, string(b))
+	return len(b), nil
+}
+
+func TestFormatTo(t *testing.T) {
+	err := fmt.Errorf("a: %v", fmt.Errorf("b: %v", errorD{}))
+	for _, e := range []error{nil, errorT{}, err} {
+		for _, detail := range []bool{false, true} {
+			var b strings.Builder
+			n, werr := errors.FormatTo(&b, e, detail)
+			want := errors.Format(e, detail)
+			if b.String() != want || n != len(want) || werr != nil {
+				t.Errorf("FormatTo(%v, %v) = %d, %v, wrote %q; want %d, nil, %q", e, detail, n, werr, b.String(), len(want), want)
+			}
+		}
+	}
+
+	w := &failingWriter{n: 1}
+	n, werr := errors.FormatTo(w, err, false)
+	if werr != io.ErrShortWrite || n != len("a: ") || len(w.writes) != 1 {
+		t.Errorf("FormatTo(failing writer) = %d, %v after %q; want %d, %v", n, werr, w.writes, len("a: "), io.ErrShortWrite)
+	}
+}
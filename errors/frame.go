@@ -5,7 +5,10 @@
 package errors
 
 import (
+	"math"
 	"runtime"
+
+	"golang.org/x/exp/errors/internal"
 )
 
 // A Frame contains part of a call stack.
@@ -14,6 +17,25 @@ type Frame struct {
 	// and possibly a PC for skipPleaseUseCallersFrames. See:
 	// https://go.googlesource.com/go/+/032678e0fb/src/runtime/extern.go#169
 	frames [3]uintptr
+
+	// loc is set for the frames of errors decoded by UnmarshalBinary, which
+	// have no program counters in this process.
+	loc *location
+}
+
+// location is the function, file and line of a frame of another process.
+type location struct {
+	function, file string
+	line           int
+}
+
+// A Framer is implemented by errors that record the location of their
+// creation, such as the errors returned by New and by the Errorf function of
+// package errors/fmt. It allows telling these errors apart from other errors
+// without exposing their concrete types.
+type Framer interface {
+	// Frame returns the location where the error was created.
+	Frame() Frame
 }
 
 // Caller returns a Frame that describes a frame on the caller's stack.
@@ -25,32 +47,118 @@ func Caller(skip int) Frame {
 	return s
 }
 
-// location reports the file, line, and function of a frame.
+// sampledCaller is like Caller, but returns a zero Frame unless the frame is
+// sampled according to the rate set with SetFrameSampleRate.
+func sampledCaller(skip int) Frame {
+	return internal.SampledCaller(Caller, skip+1)
+}
+
+// SetFrameSampleRate sets the fraction, between 0 and 1, of the errors
+// created by New, Lazy, Collector.Warn, and the Errorf and WrapIf functions
+// of package errors/fmt that record the location of their creation. The
+// others get a zero Frame, for which nothing is printed. The default rate of
+// 1 records every location; lower rates trade the completeness of the
+// locations for the cost of capturing them, which matters for errors created
+// in large numbers. The errors to sample are chosen pseudo-randomly.
 //
-// The returned function may be "" even if file and line are not.
-func (f Frame) location() (function, file string, line int) {
-	frames := runtime.CallersFrames(f.frames[:])
-	if _, ok := frames.Next(); !ok {
-		return "", "", 0
+// SetFrameSampleRate should be called early, for instance at the start of
+// main, and not concurrently with creating errors.
+func SetFrameSampleRate(rate float64) {
+	switch {
+	case rate >= 1:
+		internal.FrameSampling = false
+	case rate > 0:
+		internal.FrameSampling = true
+		internal.FrameThreshold = uint64(rate * math.MaxUint64)
+	default:
+		internal.FrameSampling = true
+		internal.FrameThreshold = 0
 	}
-	fr, ok := frames.Next()
+}
+
+// Location reports the file, line, and function of a frame.
+//
+// The returned function may be "" even if file and line are not.
+func (f Frame) Location() (function, file string, line int) {
+	fr, ok := f.frame()
 	if !ok {
 		return "", "", 0
 	}
 	return fr.Function, fr.File, fr.Line
 }
 
+// PC returns the program counter of the frame, or 0 if unknown.
+func (f Frame) PC() uintptr {
+	fr, _ := f.frame()
+	return fr.PC
+}
+
+// frame returns the runtime description of the frame.
+func (f Frame) frame() (runtime.Frame, bool) {
+	if f.loc != nil {
+		return runtime.Frame{Function: f.loc.function, File: f.loc.file, Line: f.loc.line}, true
+	}
+	frames := runtime.CallersFrames(f.frames[:])
+	if _, ok := frames.Next(); !ok {
+		return runtime.Frame{}, false
+	}
+	return frames.Next()
+}
+
+// A FrameMode selects how Frame.Format prints frames.
+type FrameMode int
+
+const (
+	// FrameModeFileLine prints the function name and the file and line of
+	// the frame. It is the default.
+	FrameModeFileLine FrameMode = iota
+
+	// FrameModePC prints the function name and the offset of the frame's
+	// program counter from the entry of the function, as in
+	// "main.run+0x4f". The offset can be resolved to a file and line with
+	// the symbol table of the binary, for instance with go tool addr2line
+	// after adding the function's address. It is meant for binaries
+	// deployed without their source paths.
+	FrameModePC
+)
+
+var frameMode FrameMode
+
+// SetFrameMode sets the way frames are printed as error detail. It affects
+// all errors in the program, so it should be called early, for instance at
+// the start of main, and not concurrently with formatting errors.
+func SetFrameMode(mode FrameMode) {
+	frameMode = mode
+}
+
 // Format prints the stack as error detail.
 // It should be called from an error's Format implementation,
 // before printing any other error detail.
+//
+// Format prints nothing, and does not call p.Detail, for a zero Frame, such
+// as the frame of an error that was not created with Caller.
+//
+// The layout of the frame depends on the mode set with SetFrameMode.
+// In FrameModePC, a frame whose function entry is unknown is printed with
+// its file and line.
 func (f Frame) Format(p Printer) {
-	if p.Detail() {
-		function, file, line := f.location()
-		if function != "" {
-			p.Printf("%s\n    ", function)
-		}
-		if file != "" {
-			p.Printf("%s:%d\n", file, line)
+	if f.frames[1] == 0 && f.loc == nil {
+		return
+	}
+	if !p.Detail() {
+		return
+	}
+	if frameMode == FrameModePC {
+		if fr, ok := f.frame(); ok && fr.Entry != 0 {
+			p.Printf("%s+%#x\n", fr.Function, fr.PC-fr.Entry)
+			return
 		}
 	}
+	function, file, line := f.Location()
+	if function != "" {
+		p.Printf("%s\n%s", function, detailIndent)
+	}
+	if file != "" {
+		p.Printf("%s:%d\n", file, line)
+	}
 }
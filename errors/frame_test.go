@@ -0,0 +1,117 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors_test
+
+import (
+	"regexp"
+	"testing"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/fmt"
+)
+
+func TestFramer(t *testing.T) {
+	err1 := errors.New("1")
+	testCases := []struct {
+		err  error
+		want bool
+	}{
+		{err1, true},
+		{fmt.Errorf("simple"), true},
+		{fmt.Errorf("wrap: %v", err1), true},
+		{errorT{}, false},
+		{errors.Opaque(err1), false},
+	}
+	for _, tc := range testCases {
+		_, ok := tc.err.(errors.Framer)
+		if ok != tc.want {
+			t.Errorf("%v.(Framer): got %v; want %v", tc.err, ok, tc.want)
+		}
+	}
+	if f := err1.(errors.Framer).Frame(); f == (errors.Frame{}) {
+		t.Errorf("New(\"1\").Frame() is zero")
+	}
+}
+
+// framed is an error with a frame set by its creator.
+type framed struct {
+	msg   string
+	frame errors.Frame
+}
+
+func (e framed) Error() string { return e.msg }
+
+func (e framed) Format(p errors.Printer) (next error) {
+	p.Print(e.msg)
+	e.frame.Format(p)
+	return nil
+}
+
+func TestZeroFrame(t *testing.T) {
+	err := fmt.Errorf("wrap: %v", framed{msg: "zero"})
+	got := fmt.Sprintf("%+v", err)
+	re := regexp.MustCompile(`^wrap:\n    golang.org/x/exp/errors_test.TestZeroFrame\n        .*frame_test.go:\d+\n--- zero$`)
+	if !re.MatchString(got) {
+		t.Errorf("%%+v: got %q; want match for %s", got, re)
+	}
+	if got := fmt.Sprintf("%+v", framed{msg: "zero"}); got != "zero" {
+		t.Errorf("%%+v: got %q; want %q", got, "zero")
+	}
+	if got := fmt.Sprintf("%+v", framed{"caller", errors.Caller(0)}); got == "caller" {
+		t.Errorf("%%+v: got %q; want location", got)
+	}
+}
+
+func TestFrameModePC(t *testing.T) {
+	defer errors.SetFrameMode(errors.FrameModeFileLine)
+
+	err := errors.New("pc")
+	f := err.(errors.Framer).Frame()
+	if f.PC() == 0 {
+		t.Errorf("PC() = 0, want non-zero")
+	}
+	if got := (errors.Frame{}).PC(); got != 0 {
+		t.Errorf("zero Frame: PC() = %#x, want 0", got)
+	}
+
+	errors.SetFrameMode(errors.FrameModePC)
+	got := fmt.Sprintf("%+v", err)
+	re := regexp.MustCompile(`^pc:\n    golang.org/x/exp/errors_test.TestFrameModePC\+0x[0-9a-f]+\n`)
+	if !re.MatchString(got) {
+		t.Errorf("%%+v: got %q; want match for %s", got, re)
+	}
+}
+
+func TestSetFrameSampleRate(t *testing.T) {
+	defer errors.SetFrameSampleRate(1)
+
+	sampled := func() (n int) {
+		for i := 0; i < 1000; i++ {
+			err := fmt.Errorf("wrap: %v", errors.New("x"))
+			n += len(errors.StackTrace(err))
+		}
+		return n
+	}
+	errors.SetFrameSampleRate(0)
+	if n := sampled(); n != 0 {
+		t.Errorf("rate 0: %d frames captured, want 0", n)
+	}
+	if got := fmt.Sprintf("%+v", errors.New("x")); got != "x" {
+		t.Errorf("rate 0: %%+v = %q, want %q", got, "x")
+	}
+	var c errors.Collector
+	c.Warn("w")
+	if n := len(errors.StackTrace(c.Flush())); n != 0 {
+		t.Errorf("rate 0: %d frames captured by Collector.Warn, want 0", n)
+	}
+	errors.SetFrameSampleRate(0.5)
+	if n := sampled(); n < 800 || n > 1200 {
+		t.Errorf("rate 0.5: %d frames captured, want about 1000", n)
+	}
+	errors.SetFrameSampleRate(1)
+	if n := sampled(); n != 2000 {
+		t.Errorf("rate 1: %d frames captured, want 2000", n)
+	}
+}
@@ -1 +1,3 @@
 module golang.org/x/exp/errors
+
+go 1.18
@@ -0,0 +1,72 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import "golang.org/x/exp/errors/internal"
+
+// SetGenerateID sets the function used to generate an ID for each error
+// created by New and by the Errorf function of package errors/fmt.
+// The ID can be shown to users as a reference and is printed as error
+// detail. By default no generator is set and errors have no ID.
+// Passing nil removes the generator.
+//
+// SetGenerateID should be called during program initialization, before
+// any errors are created.
+func SetGenerateID(generate func() string) {
+	internal.GenerateID = generate
+}
+
+// ID returns the ID of the outermost error in err's chain that has one,
+// or "" if there is none. An error has an ID if it implements
+//
+//	interface { ID() string }
+//
+// and its ID method returns a non-empty string.
+func ID(err error) string {
+	id := ""
+	Walk(err, func(err error) bool {
+		if id == "" {
+			if e, ok := err.(interface{ ID() string }); ok {
+				id = e.ID()
+			}
+		}
+		return id == ""
+	})
+	return id
+}
+
+// SetStoreTemplates sets whether the Errorf and WrapIf functions of package
+// errors/fmt keep the format string of the errors they create, for Template
+// to report. By default they do not, so as not to retain format strings that
+// are not used.
+//
+// SetStoreTemplates should be called during program initialization, before
+// any errors are created.
+func SetStoreTemplates(store bool) {
+	internal.StoreTemplates = store
+}
+
+// Template returns the format string of the outermost error in err's chain
+// that has one, and reports whether there is one. Errors created while
+// templates are stored, as set with SetStoreTemplates, by the Errorf and
+// WrapIf functions of package errors/fmt have one. As errors created with the
+// same format string are the same kind of error, whatever their arguments,
+// the template is a key for grouping them.
+//
+// More generally, an error has a template if it implements
+//
+//	interface { Template() string }
+//
+// and its Template method returns a non-empty string.
+func Template(err error) (string, bool) {
+	template := ""
+	Walk(err, func(err error) bool {
+		if e, ok := err.(interface{ Template() string }); ok {
+			template = e.Template()
+		}
+		return template == ""
+	})
+	return template, template != ""
+}
@@ -0,0 +1,86 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/fmt"
+)
+
+func TestID(t *testing.T) {
+	if got := errors.ID(errors.New("no generator")); got != "" {
+		t.Errorf("ID without generator = %q, want \"\"", got)
+	}
+
+	n := 0
+	errors.SetGenerateID(func() string {
+		n++
+		return "E" + strconv.Itoa(n)
+	})
+	defer errors.SetGenerateID(nil)
+
+	err1 := errors.New("1")                    // E1
+	erra := fmt.Errorf("wrap: %v", err1)       // E2
+	errb := fmt.Errorf("opaque: %v", errorT{}) // E3
+
+	testCases := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{errorT{}, ""},
+		{err1, "E1"},
+		{erra, "E2"},
+		{errb, "E3"},
+		{errors.Opaque(err1), ""},
+	}
+	for _, tc := range testCases {
+		if got := errors.ID(tc.err); got != tc.want {
+			t.Errorf("ID(%v) = %q, want %q", tc.err, got, tc.want)
+		}
+	}
+
+	if got := fmt.Sprintf("%v", erra); strings.Contains(got, "E1") {
+		t.Errorf("%%v: got %q; want no ID", got)
+	}
+	got := fmt.Sprintf("%+v", erra)
+	if !strings.Contains(got, "id: E2") || !strings.Contains(got, "id: E1") {
+		t.Errorf("%%+v: got %q; want IDs E2 and E1", got)
+	}
+}
+
+func TestTemplate(t *testing.T) {
+	if _, ok := errors.Template(fmt.Errorf("user %d", 1)); ok {
+		t.Errorf("Template without storing reports true")
+	}
+
+	errors.SetStoreTemplates(true)
+	defer errors.SetStoreTemplates(false)
+
+	inner := fmt.Errorf("user %d not found", 42)
+	testCases := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{errors.New("x"), ""},
+		{inner, "user %d not found"},
+		{fmt.Errorf("get %q: %v", "bob", inner), "get %q: %v"},
+		{fmt.Errorf("all: %w and %w", inner, errorT{}), "all: %w and %w"},
+		{fmt.Errorf("wrap %w", inner), "wrap %w"},
+		{fmt.WrapIf(true, inner, "lookup %s", "x"), "lookup %s"},
+		{errors.WithStatus(inner, 404), "user %d not found"},
+	}
+	for _, tc := range testCases {
+		got, ok := errors.Template(tc.err)
+		if got != tc.want || ok != (tc.want != "") {
+			t.Errorf("Template(%v) = %q, %v; want %q", tc.err, got, ok, tc.want)
+		}
+	}
+}
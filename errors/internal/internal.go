@@ -0,0 +1,140 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package internal contains functionality shared by package errors and
+// package errors/fmt.
+package internal
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// Sprint and Sprintf format the arguments passed to an errors.Printer.
+//
+// They default to the implementations of the standard library. Package
+// errors/fmt replaces them with its own so that arguments are rendered the
+// same way as in the rest of its output.
+var (
+	Sprint  = fmt.Sprint
+	Sprintf = fmt.Sprintf
+)
+
+// GenerateID is the function set by errors.SetGenerateID, or nil.
+var GenerateID func() string
+
+// NewID returns a new error ID, or "" if no generator is set.
+func NewID() string {
+	if GenerateID == nil {
+		return ""
+	}
+	return GenerateID()
+}
+
+// FormatID prints id as the detail of an error printed with p, an
+// errors.Printer, if id is set.
+func FormatID(p interface {
+	Detail() bool
+	Printf(format string, args ...interface{})
+}, id string) {
+	if id != "" && p.Detail() {
+		p.Printf("id: %s\n", id)
+	}
+}
+
+// OnCreate is set by errors.SetOnCreate, or nil.
+var OnCreate func(err error)
+
+// Created calls OnCreate, if set, for err, a newly created error, and
+// returns err.
+func Created(err error) error {
+	if OnCreate != nil {
+		OnCreate(err)
+	}
+	return err
+}
+
+// WarnDepth and Warn are set by errors.SetChainWarnThreshold.
+var (
+	WarnDepth int
+	Warn      func(depth int)
+)
+
+// CheckDepth calls Warn if a chain of the given depth is deeper than
+// WarnDepth.
+func CheckDepth(depth int) {
+	if Warn != nil && depth > WarnDepth {
+		Warn(depth)
+	}
+}
+
+// SentinelMatchByValue is set by errors.SetSentinelMatchByValue.
+var SentinelMatchByValue bool
+
+// StoreTemplates is set by errors.SetStoreTemplates.
+var StoreTemplates bool
+
+// A Cloner is an error that errors.Clone can copy.
+type Cloner interface {
+	// CloneWrapping returns a copy of the error that wraps errs instead of
+	// the errors it wraps, given in the order of its Unwrap method.
+	CloneWrapping(errs []error) error
+}
+
+// A PlainMatcher is an error of package errors or errors/fmt that records
+// whether the errors of its chain match a target only if they are equal to
+// it. This holds if they are all errors of these packages whose Is method,
+// if any, only matches by value when SentinelMatchByValue is set.
+type PlainMatcher interface {
+	PlainMatch() bool
+}
+
+// PlainMatch reports whether each of errs is nil or a PlainMatcher whose
+// chain only matches by equality.
+func PlainMatch(errs ...error) bool {
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if p, ok := err.(PlainMatcher); !ok || !p.PlainMatch() {
+			return false
+		}
+	}
+	return true
+}
+
+// FrameSampling and FrameThreshold are set by errors.SetFrameSampleRate:
+// if FrameSampling is set, a frame is captured if a pseudo-random number
+// is below FrameThreshold.
+var (
+	FrameSampling  bool
+	FrameThreshold uint64
+)
+
+var sampleState uint64
+
+// SampledCaller returns caller(skip+1), where caller is errors.Caller, if
+// the frame of a new error is sampled as reported by SampleFrame, and the
+// zero frame otherwise. As for errors.Caller, a skip of 0 stands for the
+// caller of SampledCaller.
+func SampledCaller[F any](caller func(skip int) F, skip int) F {
+	if !SampleFrame() {
+		var zero F
+		return zero
+	}
+	return caller(skip + 1)
+}
+
+// SampleFrame reports whether the frame of a new error should be captured.
+func SampleFrame() bool {
+	if !FrameSampling {
+		return true
+	}
+	// A splitmix64 generator: cheap, and safe for concurrent use.
+	x := atomic.AddUint64(&sampleState, 0x9e3779b97f4a7c15)
+	x = (x ^ x>>30) * 0xbf58476d1ce4e5b9
+	x = (x ^ x>>27) * 0x94d049bb133111eb
+	x ^= x >> 31
+	return x < FrameThreshold
+}
@@ -0,0 +1,212 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import "golang.org/x/exp/errors/internal"
+
+// Join returns an error that wraps the given errors. Nil errors are
+// discarded. Join returns nil if all errors are nil.
+//
+// The message of the returned error consists of the messages of the
+// errors, separated by "; ". When printed with detail, each error is
+// additionally printed with its own detail. The returned error has a method
+//
+//	Unwrap() []error
+//
+// returning the errors, so that Is and As match any of them.
+func Join(errs ...error) error {
+	var a []error
+	max := 0
+	for _, err := range errs {
+		if err != nil {
+			a = append(a, err)
+			if d := Depth(err); d > max {
+				max = d
+			}
+		}
+	}
+	if len(a) == 0 {
+		return nil
+	}
+	return &joinError{a, max + 1, internal.PlainMatch(a...), false}
+}
+
+// WrapSlice returns the result of Join for the non-nil errors of errs, each
+// wrapped with WrapKeepFrame and the context returned by contextFn for its
+// index, as in `field "email": invalid; field "age": out of range`. It is
+// meant for reporting errors collected per item, such as the fields of a
+// validated struct. WrapSlice returns nil if all errors are nil; contextFn is
+// only called for the non-nil errors.
+func WrapSlice(errs []error, contextFn func(i int) string) error {
+	var a []error
+	for i, err := range errs {
+		if err != nil {
+			a = append(a, WrapKeepFrame(err, contextFn(i)))
+		}
+	}
+	return Join(a...)
+}
+
+// JoinPrimary returns an error that wraps primary and the secondary errors,
+// for a main failure followed by other failures, such as errors of cleanup
+// operations. Nil errors are discarded. JoinPrimary returns primary if all
+// secondary errors are nil, and the result of Join for the secondary errors
+// if primary is nil.
+//
+// Unlike the message of Join, the message of the returned error is that of
+// primary, followed by the secondary errors as notes, as in
+// "write failed (also: close failed; unlock failed)". When printed with
+// detail, primary is printed with its detail first. As for Join, the
+// returned error has a method Unwrap() []error returning all the errors,
+// primary first, so that Is and As match any of them.
+func JoinPrimary(primary error, secondary ...error) error {
+	if primary == nil {
+		return Join(secondary...)
+	}
+	err := Join(append([]error{primary}, secondary...)...).(*joinError)
+	if len(err.errs) == 1 {
+		return primary
+	}
+	err.primary = true
+	return err
+}
+
+type joinError struct {
+	errs  []error
+	depth int
+	plain bool
+
+	// primary reports whether the first error is the main one, as in the
+	// errors returned by JoinPrimary.
+	primary bool
+}
+
+func (e *joinError) Error() string {
+	return Format(e, false)
+}
+
+func (e *joinError) Format(p Printer) (next error) {
+	if e.primary {
+		return e.formatPrimary(p)
+	}
+	for i, err := range e.errs {
+		if i > 0 {
+			p.Print("; ")
+		}
+		p.Print(Format(err, false))
+	}
+	if p.Detail() {
+		for i, err := range e.errs {
+			p.Printf("[%d] %s\n", i, Format(err, true))
+		}
+	}
+	return nil
+}
+
+func (e *joinError) formatPrimary(p Printer) (next error) {
+	p.Print(Format(e.errs[0], false), " (also: ")
+	for i, err := range e.errs[1:] {
+		if i > 0 {
+			p.Print("; ")
+		}
+		p.Print(Format(err, false))
+	}
+	p.Print(")")
+	if p.Detail() {
+		p.Printf("%s\n", Format(e.errs[0], true))
+		for _, err := range e.errs[1:] {
+			p.Printf("also: %s\n", Format(err, true))
+		}
+	}
+	return nil
+}
+
+func (e *joinError) PlainMatch() bool { return e.plain }
+
+func (e *joinError) Depth() int {
+	return e.depth
+}
+
+func (e *joinError) Unwrap() []error {
+	return e.errs
+}
+
+func (e *joinError) CloneWrapping(errs []error) error {
+	c := *e
+	c.errs = errs
+	return &c
+}
+
+// SameSet reports whether the trees of x and y have the same set of leaf
+// errors, regardless of their order, of duplicates and of how they are
+// nested, so that Join(a, b) and Join(b, Join(a)) are the same. The leaves
+// of a tree are the errors of its chain, as visited by Walk, that wrap no
+// errors. Two leaves are the same if they are equal or if either matches the
+// other with its Is method.
+//
+// If the leaves are comparable and none has an Is method, the sets are
+// compared with a map in linear time. Otherwise, each leaf of x is compared
+// with the leaves of y and vice versa, in O(n×m) time for n and m leaves.
+func SameSet(x, y error) bool {
+	xs, xplain := leaves(x)
+	ys, yplain := leaves(y)
+	if xplain && yplain {
+		set := make(map[error]bool, len(xs))
+		for _, err := range xs {
+			set[err] = false
+		}
+		for _, err := range ys {
+			if _, ok := set[err]; !ok {
+				return false
+			}
+			set[err] = true
+		}
+		for _, seen := range set {
+			if !seen {
+				return false
+			}
+		}
+		return true
+	}
+	return subset(xs, ys) && subset(ys, xs)
+}
+
+// leaves returns the leaf errors of err's tree, as defined by SameSet, and
+// reports whether they can be compared with == only, as they are comparable
+// and neither have an Is method nor implement Target.
+func leaves(err error) (errs []error, plain bool) {
+	plain = true
+	Walk(err, func(err error) bool {
+		if _, ok := err.(interface{ Unwrap() []error }); ok || Unwrap(err) != nil {
+			return true
+		}
+		errs = append(errs, err)
+		_, hasIs := err.(interface{ Is(error) bool })
+		_, isTarget := err.(Target)
+		if hasIs || isTarget || !isComparable(err) {
+			plain = false
+		}
+		return true
+	})
+	return errs, plain
+}
+
+// subset reports whether each error of xs is the same as some error of ys,
+// as defined by SameSet.
+func subset(xs, ys []error) bool {
+	for _, x := range xs {
+		found := false
+		for _, y := range ys {
+			if matches(x, y, isComparable(y)) || matches(y, x, isComparable(x)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,229 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors_test
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/fmt"
+)
+
+func TestJoin(t *testing.T) {
+	err1 := errors.New("1")
+	err2 := errors.New("2")
+
+	if err := errors.Join(); err != nil {
+		t.Errorf("Join() = %v, want nil", err)
+	}
+	if err := errors.Join(nil, nil); err != nil {
+		t.Errorf("Join(nil, nil) = %v, want nil", err)
+	}
+
+	err := errors.Join(err1, nil, fmt.Errorf("wrap: %v", err2))
+	if got, want := err.Error(), "1; wrap: 2"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if got, want := fmt.Sprintf("wrap: %v", err), "wrap: 1; wrap: 2"; got != want {
+		t.Errorf("%%v = %q, want %q", got, want)
+	}
+	for _, target := range []error{err1, err2} {
+		if !errors.Is(err, target) {
+			t.Errorf("Is(%v, %v) = false, want true", err, target)
+		}
+	}
+}
+
+func TestJoinDetail(t *testing.T) {
+	err := errors.Join(errorD{}, errorT{})
+	want := "errorD; errorT:" +
+		"\n    [0] errorD:" +
+		"\n        detail" +
+		"\n    [1] errorT" +
+		"\n    "
+	if got := fmt.Sprintf("%+v", err); got != want {
+		t.Errorf("%%+v:\n got: %q\nwant: %q", got, want)
+	}
+}
+
+func TestCollector(t *testing.T) {
+	var c errors.Collector
+	if err := c.Flush(); err != nil {
+		t.Errorf("empty Flush() = %v, want nil", err)
+	}
+
+	err1 := errors.New("1")
+	c.Add(nil)
+	c.Add(err1)
+	c.Warn("disk %d%% full", 90)
+	err := c.Flush()
+	if got, want := err.Error(), "1; disk 90% full"; got != want {
+		t.Errorf("Flush() = %q, want %q", got, want)
+	}
+	if !errors.Is(err, err1) {
+		t.Errorf("Is(%v, %v) = false, want true", err, err1)
+	}
+	if err := c.Flush(); err != nil {
+		t.Errorf("second Flush() = %v, want nil", err)
+	}
+}
+
+func TestCollectorFlushTo(t *testing.T) {
+	err1 := errors.New("1")
+	f := func(ret error) (err error) {
+		var c errors.Collector
+		defer c.FlushTo(&err)
+		c.Warn("warning")
+		return ret
+	}
+	if got, want := f(nil).Error(), "warning"; got != want {
+		t.Errorf("FlushTo(nil) = %q, want %q", got, want)
+	}
+	err := f(err1)
+	if got, want := err.Error(), "1; warning"; got != want {
+		t.Errorf("FlushTo(err1) = %q, want %q", got, want)
+	}
+	if !errors.Is(err, err1) {
+		t.Errorf("Is(%v, %v) = false, want true", err, err1)
+	}
+}
+
+func TestDrain(t *testing.T) {
+	ch := make(chan error)
+	errs := []error{errors.New("1"), nil, errors.New("2"), errors.New("3")}
+	go func() {
+		defer close(ch)
+		for _, err := range errs {
+			ch <- err
+		}
+	}()
+	err := errors.Drain(ch)
+	if got, want := err.Error(), "1; 2; 3"; got != want {
+		t.Errorf("Drain() = %q, want %q", got, want)
+	}
+	for _, e := range errs {
+		if e != nil && !errors.Is(err, e) {
+			t.Errorf("Is(%v, %v) = false, want true", err, e)
+		}
+	}
+
+	empty := make(chan error, 1)
+	empty <- nil
+	close(empty)
+	if err := errors.Drain(empty); err != nil {
+		t.Errorf("Drain(nil errors) = %v, want nil", err)
+	}
+}
+
+func TestBuffer(t *testing.T) {
+	var b errors.Buffer
+	if err := b.Err(); err != nil {
+		t.Errorf("empty Err() = %v, want nil", err)
+	}
+
+	errPerm := errors.New("permission denied")
+	b.Add(nil)
+	for _, name := range []string{"a", "b", "c"} {
+		b.Add(fmt.Errorf("open %s: %v", name, errPerm))
+	}
+	b.Add(errors.New("disk full"))
+	b.Add(fmt.Errorf("open d: %v", errors.New("permission denied")))
+	err := b.Err()
+	if got, want := err.Error(), "open a: permission denied (×4); disk full"; got != want {
+		t.Errorf("Err() = %q, want %q", got, want)
+	}
+	if !errors.Is(err, errPerm) {
+		t.Errorf("Is(%v, %v) = false, want true", err, errPerm)
+	}
+
+	var cycles errors.Buffer
+	for i := 0; i < 2; i++ {
+		loop := &cyclic{msg: "loop"}
+		loop.next = &cyclic{"loop 2", loop}
+		cycles.Add(loop)
+	}
+	if got := cycles.Err().Error(); !strings.Contains(got, "×2") {
+		t.Errorf("Err() of cycles = %q, want them grouped", got)
+	}
+}
+
+func TestSameSet(t *testing.T) {
+	a := errors.New("a")
+	b := errors.New("b")
+	c := errors.New("c")
+	testCases := []struct {
+		x, y error
+		want bool
+	}{
+		{nil, nil, true},
+		{a, nil, false},
+		{a, a, true},
+		{errors.Join(a, b), errors.Join(b, a), true},
+		{errors.Join(a, b), errors.Join(b, errors.Join(a, a)), true},
+		{errors.Join(a, b), errors.Join(a, c), false},
+		{errors.Join(a, b), errors.Join(a, b, c), false},
+		{errors.Join(a, b, c), errors.Join(a, b), false},
+		{errors.Join(errorIs("errorT"), a), errors.Join(a, errorT{}), true},
+		{errors.Join(errorIs("errorT"), a), errors.Join(a, b), false},
+	}
+	for _, tc := range testCases {
+		if got := errors.SameSet(tc.x, tc.y); got != tc.want {
+			t.Errorf("SameSet(%v, %v) = %v, want %v", tc.x, tc.y, got, tc.want)
+		}
+	}
+}
+
+func TestWrapSlice(t *testing.T) {
+	fields := []string{"name", "email", "age"}
+	field := func(i int) string { return fmt.Sprintf("field %q", fields[i]) }
+
+	errInvalid := errors.New("invalid")
+	err := errors.WrapSlice([]error{nil, errInvalid, errors.New("out of range")}, field)
+	if got, want := err.Error(), `field "email": invalid; field "age": out of range`; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if !errors.Is(err, errInvalid) {
+		t.Errorf("Is(err, errInvalid) = false, want true")
+	}
+	if err := errors.WrapSlice(make([]error, 3), field); err != nil {
+		t.Errorf("WrapSlice(nil errors) = %v, want nil", err)
+	}
+}
+
+func TestJoinPrimary(t *testing.T) {
+	errWrite := errors.New("write failed")
+	errClose := errors.New("close failed")
+	errUnlock := fmt.Errorf("unlock: %v", errorT{})
+
+	err := errors.JoinPrimary(errWrite, errClose, nil, errUnlock)
+	if got, want := err.Error(), "write failed (also: close failed; unlock: errorT)"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	for _, target := range []error{errWrite, errClose, errUnlock} {
+		if !errors.Is(err, target) {
+			t.Errorf("Is(err, %v) = false, want true", target)
+		}
+	}
+	var e errorT
+	if !errors.As(err, &e) {
+		t.Errorf("As(err, &errorT) = false, want true")
+	}
+
+	detail := fmt.Sprintf("%+v", errors.JoinPrimary(errorD{}, errorT{}))
+	if want := "errorD (also: errorT):\n    errorD:\n        detail\n    also: errorT\n    "; detail != want {
+		t.Errorf("%%+v = %q, want %q", detail, want)
+	}
+
+	if got := errors.JoinPrimary(errWrite, nil); got != errWrite {
+		t.Errorf("JoinPrimary(errWrite, nil) = %v, want errWrite", got)
+	}
+	if got, want := fmt.Sprint(errors.JoinPrimary(nil, errClose, errWrite)), "close failed; write failed"; got != want {
+		t.Errorf("JoinPrimary(nil, ...) = %q, want %q", got, want)
+	}
+	if got := errors.JoinPrimary(nil); got != nil {
+		t.Errorf("JoinPrimary(nil) = %v, want nil", got)
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"sync"
+
+	"golang.org/x/exp/errors/internal"
+)
+
+// Lazy returns an error like New whose text is computed by calling fn the
+// first time the error is printed, and reused thereafter. It is meant for
+// errors that are costly to describe, such as a difference between two large
+// values, and are often discarded without being printed.
+//
+// The location of the caller is recorded when Lazy is called. fn is called
+// at most once, possibly concurrently with other uses of the error, and
+// must not use the error itself; as its result is kept, it should not
+// depend on state that may change after the error was created.
+func Lazy(fn func() string) error {
+	return &lazyError{fn: fn, frame: sampledCaller(1), id: internal.NewID()}
+}
+
+type lazyError struct {
+	once  sync.Once
+	fn    func() string
+	s     string
+	frame Frame
+	id    string
+}
+
+func (e *lazyError) text() string {
+	e.once.Do(func() {
+		e.s = e.fn()
+		e.fn = nil
+	})
+	return e.s
+}
+
+func (e *lazyError) Error() string {
+	if renderOptions != (RenderOptions{}) {
+		return Format(e, false)
+	}
+	return e.text()
+}
+
+func (e *lazyError) Format(p Printer) (next error) {
+	p.Print(e.text())
+	e.frame.Format(p)
+	internal.FormatID(p, e.id)
+	return nil
+}
+
+func (e *lazyError) PlainMatch() bool { return true }
+
+func (e *lazyError) Frame() Frame {
+	return e.frame
+}
+
+func (e *lazyError) Depth() int {
+	return 1
+}
+
+func (e *lazyError) ID() string {
+	return e.id
+}
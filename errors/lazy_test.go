@@ -0,0 +1,38 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors_test
+
+import (
+	"regexp"
+	"testing"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/fmt"
+)
+
+func TestLazy(t *testing.T) {
+	calls := 0
+	err := errors.Lazy(func() string {
+		calls++
+		return "expensive"
+	})
+	if calls != 0 {
+		t.Fatalf("Lazy called fn on creation")
+	}
+	if got, want := err.Error(), "expensive"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if got, want := fmt.Sprintf("wrap: %v", err), "wrap: expensive"; got != want {
+		t.Errorf("Sprintf = %q, want %q", got, want)
+	}
+	got := fmt.Sprintf("%+v", err)
+	re := regexp.MustCompile(`^expensive:\n    golang.org/x/exp/errors_test.TestLazy\n        .*lazy_test.go:\d+\n`)
+	if !re.MatchString(got) {
+		t.Errorf("%%+v: got %q; want match for %s", got, re)
+	}
+	if calls != 1 {
+		t.Errorf("fn called %d times, want 1", calls)
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+// WithMeta returns an error that annotates err with meta, which can be
+// retrieved by its type with Meta. The returned error formats as err and
+// unwraps to err. WithMeta returns nil if err is nil.
+func WithMeta[T any](err error, meta T) error {
+	if err == nil {
+		return nil
+	}
+	return &withMeta[T]{err, meta}
+}
+
+type withMeta[T any] struct {
+	err  error
+	meta T
+}
+
+func (e *withMeta[T]) Error() string { return e.err.Error() }
+
+func (e *withMeta[T]) Format(p Printer) (next error) {
+	return formatInPlace(p, e.err)
+}
+
+func (e *withMeta[T]) Unwrap() error { return e.err }
+
+func (e *withMeta[T]) CloneWrapping(errs []error) error {
+	c := *e
+	c.err = errs[0]
+	return &c
+}
+
+// Meta returns the outermost value of type T attached to err's chain with
+// WithMeta. It reports false if there is none.
+func Meta[T any](err error) (meta T, ok bool) {
+	Walk(err, func(err error) bool {
+		var m *withMeta[T]
+		if m, ok = err.(*withMeta[T]); ok {
+			meta = m.meta
+		}
+		return !ok
+	})
+	return meta, ok
+}
@@ -0,0 +1,44 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors_test
+
+import (
+	"testing"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/fmt"
+)
+
+type requestID string
+
+type retry struct{ after int }
+
+func TestMeta(t *testing.T) {
+	err1 := errors.New("1")
+	err := errors.WithMeta(fmt.Errorf("wrap: %v", errors.WithMeta(err1, requestID("inner"))), requestID("outer"))
+	err = errors.WithMeta(err, retry{3})
+
+	if got, ok := errors.Meta[requestID](err); !ok || got != "outer" {
+		t.Errorf("Meta[requestID] = %q, %v; want %q, true", got, ok, "outer")
+	}
+	if got, ok := errors.Meta[retry](err); !ok || got.after != 3 {
+		t.Errorf("Meta[retry] = %v, %v; want {3}, true", got, ok)
+	}
+	if got, ok := errors.Meta[int](err); ok {
+		t.Errorf("Meta[int] = %v, true; want false", got)
+	}
+	if _, ok := errors.Meta[requestID](nil); ok {
+		t.Errorf("Meta[requestID](nil): got true, want false")
+	}
+	if err := errors.WithMeta(nil, 1); err != nil {
+		t.Errorf("WithMeta(nil, 1) = %v, want nil", err)
+	}
+	if got, want := fmt.Sprint(err), "wrap: 1"; got != want {
+		t.Errorf("Sprint = %q, want %q", got, want)
+	}
+	if !errors.Is(err, err1) {
+		t.Errorf("Is(err, err1) = false, want true")
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package net inspects the errors of package net that are wrapped with
+// package golang.org/x/exp/errors. It is separate from that package so that
+// the latter does not depend on package net.
+package net
+
+import (
+	"net"
+
+	"golang.org/x/exp/errors"
+)
+
+// IsNetworkError reports whether any error in err's chain implements
+// net.Error, as do the errors of package net and the *url.Error errors of
+// package net/url.
+func IsNetworkError(err error) bool {
+	_, ok := errors.Capability[net.Error](err)
+	return ok
+}
+
+// NetOp returns the operation, network and remote address, or local address
+// if there is no remote one, of the first *net.OpError in err's chain, as
+// found by errors.As. It reports false if there is none. The address is ""
+// if the error records neither.
+func NetOp(err error) (op, network, addr string, ok bool) {
+	var e *net.OpError
+	if !errors.As(err, &e) {
+		return "", "", "", false
+	}
+	switch {
+	case e.Addr != nil:
+		addr = e.Addr.String()
+	case e.Source != nil:
+		addr = e.Source.String()
+	}
+	return e.Op, e.Net, addr, true
+}
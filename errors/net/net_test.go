@@ -0,0 +1,44 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package net_test
+
+import (
+	stdnet "net"
+	"net/url"
+	"os"
+	"testing"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/fmt"
+	"golang.org/x/exp/errors/net"
+)
+
+func TestNetworkErrors(t *testing.T) {
+	addr := &stdnet.TCPAddr{IP: stdnet.IPv4(127, 0, 0, 1), Port: 80}
+	opErr := &stdnet.OpError{Op: "dial", Net: "tcp", Addr: addr, Err: os.ErrDeadlineExceeded}
+	urlErr := &url.Error{Op: "Get", URL: "http://127.0.0.1", Err: opErr}
+	testCases := []struct {
+		err               error
+		network           bool
+		op, netw, address string
+		ok                bool
+	}{
+		{nil, false, "", "", "", false},
+		{errors.New("x"), false, "", "", "", false},
+		{fmt.Errorf("fetch: %v", urlErr), true, "dial", "tcp", "127.0.0.1:80", true},
+		{&stdnet.OpError{Op: "read", Net: "udp", Err: os.ErrClosed}, true, "read", "udp", "", true},
+		{&stdnet.DNSError{Err: "no such host", Name: "x.invalid"}, true, "", "", "", false},
+	}
+	for _, tc := range testCases {
+		if got := net.IsNetworkError(tc.err); got != tc.network {
+			t.Errorf("IsNetworkError(%v) = %v, want %v", tc.err, got, tc.network)
+		}
+		op, netw, address, ok := net.NetOp(tc.err)
+		if op != tc.op || netw != tc.netw || address != tc.address || ok != tc.ok {
+			t.Errorf("NetOp(%v) = %q, %q, %q, %v; want %q, %q, %q, %v",
+				tc.err, op, netw, address, ok, tc.op, tc.netw, tc.address, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"runtime"
+
+	"golang.org/x/exp/errors/internal"
+)
+
+// NewStack returns an error like New that records up to depth frames of the
+// stack of its caller, rather than only its location, for errors whose
+// origin matters enough to justify the cost, such as failures of top-level
+// handlers. The argument skip is the number of frames to skip over, as for
+// Caller: NewStack(0, ...) records the stack from the caller of NewStack.
+//
+// When printed with detail, the error shows the frames after its message,
+// innermost first. Its Frame method returns the first one. A depth of less
+// than 1 records a single frame.
+func NewStack(skip, depth int, msg string) error {
+	return newStack(skip+1, depth, msg)
+}
+
+// ErrorfStack is like NewStack with a skip of 0 and a message formatted
+// according to a format specifier, as with Collector.Warn. Unlike the Errorf
+// function of package errors/fmt, it does not wrap its error operands.
+func ErrorfStack(depth int, format string, a ...interface{}) error {
+	return newStack(1, depth, internal.Sprintf(format, a...))
+}
+
+// newStack implements NewStack and ErrorfStack, which must call it directly.
+func newStack(skip, depth int, msg string) error {
+	if depth < 1 {
+		depth = 1
+	}
+	// Each Frame holds the PC of the function its location calls, first, as
+	// set by Caller, so one more PC is recorded, and one past the end for
+	// the last frame.
+	pcs := make([]uintptr, depth+2)
+	n := runtime.Callers(skip+1, pcs)
+	var frames []Frame
+	for i := 0; i+1 < n && i < depth; i++ {
+		var f Frame
+		copy(f.frames[:], pcs[i:n])
+		frames = append(frames, f)
+	}
+	return internal.Created(&stackError{msg, frames, internal.NewID()})
+}
+
+type stackError struct {
+	s      string
+	frames []Frame
+	id     string
+}
+
+func (e *stackError) Error() string {
+	if renderOptions != (RenderOptions{}) {
+		return Format(e, false)
+	}
+	return e.s
+}
+
+func (e *stackError) Format(p Printer) (next error) {
+	p.Print(e.s)
+	for _, f := range e.frames {
+		f.Format(p)
+	}
+	internal.FormatID(p, e.id)
+	return nil
+}
+
+func (e *stackError) PlainMatch() bool { return true }
+
+func (e *stackError) Frame() Frame {
+	if len(e.frames) == 0 {
+		return Frame{}
+	}
+	return e.frames[0]
+}
+
+func (e *stackError) Depth() int {
+	return 1
+}
+
+func (e *stackError) ID() string {
+	return e.id
+}
@@ -0,0 +1,32 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import "reflect"
+
+// IsNil reports whether err is nil or holds a nil pointer, map, slice,
+// function or channel: a "typed nil" such as the result of
+//
+//	func f() error {
+//		var e *MyError
+//		return e
+//	}
+//
+// which compares unequal to nil although it carries no error.
+//
+// IsNil uses reflection for non-nil errors, which makes it considerably more
+// expensive than a comparison with nil. It is meant for the boundaries of a
+// program where errors of unknown origin are received, not for checking
+// every error.
+func IsNil(err error) bool {
+	if err == nil {
+		return true
+	}
+	switch v := reflect.ValueOf(err); v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors_test
+
+import (
+	"testing"
+
+	"golang.org/x/exp/errors"
+)
+
+type ptrErr struct{}
+
+func (*ptrErr) Error() string { return "ptrErr" }
+
+func typedNil() error {
+	var e *ptrErr
+	return e
+}
+
+func TestIsNil(t *testing.T) {
+	testCases := []struct {
+		err  error
+		want bool
+	}{
+		{nil, true},
+		{typedNil(), true},
+		{multi(nil), true},
+		{&ptrErr{}, false},
+		{errorT{}, false},
+		{multi{}, false},
+		{errors.New("x"), false},
+	}
+	for _, tc := range testCases {
+		if got := errors.IsNil(tc.err); got != tc.want {
+			t.Errorf("IsNil(%#v) = %v, want %v", tc.err, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import "regexp"
+
+// Default replacers of Normalize, applied in this order.
+var (
+	// NormalizeUUID matches UUIDs, replaced by "<id>".
+	NormalizeUUID = regexp.MustCompile(`(?P<id>\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b)`)
+
+	// NormalizeHex matches hexadecimal numbers such as addresses, replaced by
+	// "<addr>".
+	NormalizeHex = regexp.MustCompile(`(?P<addr>\b0[xX][0-9a-fA-F]+\b)`)
+
+	// NormalizeInt matches decimal integers, replaced by "<num>".
+	NormalizeInt = regexp.MustCompile(`(?P<num>\b\d+\b)`)
+)
+
+var defaultReplacers = []*regexp.Regexp{NormalizeUUID, NormalizeHex, NormalizeInt}
+
+// Normalize returns the text of err, as printed by Format without detail,
+// with the variable parts of its messages, such as identifiers and numbers,
+// replaced by placeholders, for use as a key grouping similar errors.
+//
+// Each replacer is applied in turn to the result of the previous one, and
+// replaces its matches with "<name>", where name is the name of its first
+// named subexpression, such as id in `(?P<id>[0-9]+)`, or with "<>" if it has
+// none. Without replacers, Normalize uses NormalizeUUID, NormalizeHex and
+// NormalizeInt, in this order. Normalize returns "" if err is nil.
+func Normalize(err error, replacers ...*regexp.Regexp) string {
+	if err == nil {
+		return ""
+	}
+	if len(replacers) == 0 {
+		replacers = defaultReplacers
+	}
+	s := Format(err, false)
+	for _, re := range replacers {
+		s = re.ReplaceAllLiteralString(s, "<"+placeholder(re)+">")
+	}
+	return s
+}
+
+// placeholder returns the name of the first named subexpression of re.
+func placeholder(re *regexp.Regexp) string {
+	for _, name := range re.SubexpNames() {
+		if name != "" {
+			return name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,38 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors_test
+
+import (
+	"regexp"
+	"testing"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/fmt"
+)
+
+func TestNormalize(t *testing.T) {
+	path := regexp.MustCompile(`(?P<path>/[^ :]+)`)
+	testCases := []struct {
+		err       error
+		replacers []*regexp.Regexp
+		want      string
+	}{
+		{nil, nil, ""},
+		{errors.New("no variable parts"), nil, "no variable parts"},
+		{
+			fmt.Errorf("user 42: %v", errors.New("session 123e4567-e89b-12d3-a456-426614174000 expired")),
+			nil,
+			"user <num>: session <id> expired",
+		},
+		{errors.New("bad pointer 0xc000012345 in v2"), nil, "bad pointer <addr> in v2"},
+		{errors.New("open /tmp/x17: denied"), []*regexp.Regexp{path}, "open <path>: denied"},
+		{errors.New("retry 3"), []*regexp.Regexp{regexp.MustCompile(`\d`)}, "retry <>"},
+	}
+	for _, tc := range testCases {
+		if got := errors.Normalize(tc.err, tc.replacers...); got != tc.want {
+			t.Errorf("Normalize(%v) = %q, want %q", tc.err, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+// An IsOption changes the way IsOpts examines the errors of a chain.
+type IsOption func(*isOptions)
+
+type isOptions struct {
+	opaque   bool
+	maxDepth int // negative for no limit
+}
+
+// PenetrateOpaque makes IsOpts also examine the errors hidden by Opaque, as
+// AsInternal does for As. It is meant for trusted contexts only.
+func PenetrateOpaque() IsOption {
+	return func(o *isOptions) { o.opaque = true }
+}
+
+// MaxDepth makes IsOpts examine at most the first n errors of the chain, as
+// IsWithin does.
+func MaxDepth(n int) IsOption {
+	return func(o *isOptions) { o.maxDepth = n }
+}
+
+// IsOpts is like Is, with its traversal of err's chain changed by opts.
+// Without options, it is equivalent to Is, which should be preferred then.
+func IsOpts(err, target error, opts ...IsOption) bool {
+	o := isOptions{maxDepth: -1}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	walk := Walk
+	if o.opaque {
+		walk = walkInternal
+	}
+	if o.maxDepth >= 0 {
+		walk = limitWalk(walk, o.maxDepth)
+	}
+	return is(err, target, walk)
+}
@@ -0,0 +1,35 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors_test
+
+import (
+	"testing"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/fmt"
+)
+
+func TestIsOpts(t *testing.T) {
+	err1 := errors.New("1")
+	err := fmt.Errorf("public: %v", errors.Opaque(fmt.Errorf("inner: %v", err1)))
+	testCases := []struct {
+		opts []errors.IsOption
+		want bool
+	}{
+		{nil, false},
+		{[]errors.IsOption{errors.PenetrateOpaque()}, true},
+		{[]errors.IsOption{errors.PenetrateOpaque(), errors.MaxDepth(4)}, true},
+		{[]errors.IsOption{errors.PenetrateOpaque(), errors.MaxDepth(3)}, false},
+		{[]errors.IsOption{errors.MaxDepth(10)}, false},
+	}
+	for _, tc := range testCases {
+		if got := errors.IsOpts(err, err1, tc.opts...); got != tc.want {
+			t.Errorf("IsOpts(err, err1, %d options) = %v, want %v", len(tc.opts), got, tc.want)
+		}
+	}
+	if !errors.IsOpts(err, err) || errors.IsOpts(err, err, errors.MaxDepth(0)) {
+		t.Errorf("IsOpts(err, err): MaxDepth not honored")
+	}
+}
@@ -0,0 +1,29 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import "os"
+
+// PathOf returns the path of the first *os.PathError or *os.LinkError in
+// err's chain and reports whether there is one.
+//
+// For an *os.LinkError, which involves two paths, PathOf returns the Old
+// path: it is the one that must exist for the operation to succeed and
+// therefore the one most errors are about.
+func PathOf(err error) (path string, ok bool) {
+	Walk(err, func(err error) bool {
+		if ok {
+			return false
+		}
+		switch e := err.(type) {
+		case *os.PathError:
+			path, ok = e.Path, true
+		case *os.LinkError:
+			path, ok = e.Old, true
+		}
+		return !ok
+	})
+	return path, ok
+}
@@ -0,0 +1,38 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors_test
+
+import (
+	"os"
+	"testing"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/fmt"
+)
+
+func TestPathOf(t *testing.T) {
+	_, errF := os.Open("non-existing")
+	errL := os.Link("non-existing-old", "non-existing-new")
+
+	testCases := []struct {
+		err  error
+		path string
+		ok   bool
+	}{
+		{nil, "", false},
+		{errorT{}, "", false},
+		{errF, "non-existing", true},
+		{fmt.Errorf("open config: %v", errF), "non-existing", true},
+		{errL, "non-existing-old", true},
+		{multi{errorT{}, errL, errF}, "non-existing-old", true},
+		{errors.Opaque(errF), "", false},
+	}
+	for _, tc := range testCases {
+		path, ok := errors.PathOf(tc.err)
+		if path != tc.path || ok != tc.ok {
+			t.Errorf("PathOf(%v) = %q, %v; want %q, %v", tc.err, path, ok, tc.path, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"encoding/json"
+	"regexp"
+)
+
+// A PolicyRule describes a class of errors in a Policy. An error matches a
+// rule if it meets all the conditions set in the rule, which must set at
+// least one:
+//
+//   - Type: an error in its chain has this dynamic type, as for IsTypeName.
+//   - Message: the text of an error in its chain, as returned by its Error
+//     method, matches this regular expression, as for Contains.
+//   - Status: its HTTP status, as returned by Status, is this code.
+//
+// In JSON, the fields are named type, message and status.
+type PolicyRule struct {
+	Type    string `json:"type,omitempty"`
+	Message string `json:"message,omitempty"`
+	Status  int    `json:"status,omitempty"`
+}
+
+// A Policy classifies errors according to rules that can be loaded from
+// configuration, such as the definition of the errors worth retrying.
+type Policy struct {
+	rules []policyRule
+}
+
+type policyRule struct {
+	PolicyRule
+	message *regexp.Regexp
+}
+
+// NewPolicy returns a Policy with the given rules. It returns an error if a
+// rule sets no condition or has an invalid regular expression.
+func NewPolicy(rules ...PolicyRule) (*Policy, error) {
+	p := &Policy{}
+	for _, r := range rules {
+		if r == (PolicyRule{}) {
+			return nil, New("errors: policy rule without condition")
+		}
+		pr := policyRule{PolicyRule: r}
+		if r.Message != "" {
+			re, err := regexp.Compile(r.Message)
+			if err != nil {
+				return nil, err
+			}
+			pr.message = re
+		}
+		p.rules = append(p.rules, pr)
+	}
+	return p, nil
+}
+
+// ParsePolicy returns a Policy with the rules of a JSON document of the form
+//
+//	{"rules": [{"type": "*net.OpError"}, {"message": "(?i)timeout", "status": 503}]}
+//
+// It returns an error if the document is invalid or if NewPolicy fails.
+func ParsePolicy(data []byte) (*Policy, error) {
+	var doc struct {
+		Rules []PolicyRule `json:"rules"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return NewPolicy(doc.Rules...)
+}
+
+// Matches reports whether err matches any rule of p. The rules are tried in
+// order, and their conditions in the order Type, Message, Status, each
+// evaluation stopping as soon as the result is known. A nil error matches no
+// rule.
+func (p *Policy) Matches(err error) bool {
+	if err == nil {
+		return false
+	}
+	for _, r := range p.rules {
+		if r.matches(err) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *policyRule) matches(err error) bool {
+	if r.Type != "" && !IsTypeName(err, r.Type) {
+		return false
+	}
+	if r.message != nil && !containsFunc(err, r.message.MatchString) {
+		return false
+	}
+	return r.Status == 0 || Status(err) == r.Status
+}
@@ -0,0 +1,54 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors_test
+
+import (
+	"net"
+	"os"
+	"testing"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/fmt"
+)
+
+func TestPolicy(t *testing.T) {
+	p, err := errors.ParsePolicy([]byte(`{"rules": [
+		{"type": "*net.OpError"},
+		{"message": "(?i)too many requests", "status": 429},
+		{"message": "^connection reset"}
+	]}`))
+	if err != nil {
+		t.Fatalf("ParsePolicy: %v", err)
+	}
+	testCases := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{errors.New("invalid input"), false},
+		{fmt.Errorf("dial: %v", &net.OpError{Op: "dial", Err: os.ErrDeadlineExceeded}), true},
+		{errors.WithStatus(errors.New("Too Many Requests"), 429), true},
+		{errors.New("too many requests"), false},
+		{fmt.Errorf("read: %v", errors.New("connection reset by peer")), true},
+		{errors.New("read: connection reset by peer"), false},
+	}
+	for _, tc := range testCases {
+		if got := p.Matches(tc.err); got != tc.want {
+			t.Errorf("Matches(%v) = %v, want %v", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestPolicyErrors(t *testing.T) {
+	for _, data := range []string{
+		`{"rules": [{}]}`,
+		`{"rules": [{"message": "("}]}`,
+		`{"rules": `,
+	} {
+		if _, err := errors.ParsePolicy([]byte(data)); err == nil {
+			t.Errorf("ParsePolicy(%s): got nil error", data)
+		}
+	}
+}
@@ -0,0 +1,385 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/exp/errors/internal"
+)
+
+// Format returns the text of err as printed by the %v verb or, if detail is
+// true, by the %+v verb of package golang.org/x/exp/errors/fmt.
+// Without detail, the output is controlled by the RenderOptions set with
+// SetRenderOptions.
+//
+// Format is the canonical renderer of error chains: the fmt verbs are
+// implemented in terms of it.
+func Format(err error, detail bool) string {
+	if err == nil {
+		return "<nil>"
+	}
+	p := &printer{detail: detail}
+	if !detail {
+		p.opts = renderOptions
+	}
+	p.format(err)
+	return string(p.buf)
+}
+
+// FormatTo writes the text of err, as returned by Format, to w, and returns
+// the number of bytes written and any write error encountered. The text is
+// written as the errors of the chain are printed, rather than built first,
+// and printing stops at the first write error.
+func FormatTo(w io.Writer, err error, detail bool) (n int, werr error) {
+	if err == nil {
+		return io.WriteString(w, "<nil>")
+	}
+	p := &printer{detail: detail, w: w}
+	if !detail {
+		p.opts = renderOptions
+	}
+	p.format(err)
+	return p.n, p.werr
+}
+
+// Sprint returns the text of err, with detail if detail is true. It is the
+// same as Format and is meant for code that decides at run time whether to
+// include detail, as an alternative to choosing between the %v and %+v verbs.
+func Sprint(err error, detail bool) string {
+	return Format(err, detail)
+}
+
+// Sprintln is like Sprint but appends a newline.
+func Sprintln(err error, detail bool) string {
+	return Format(err, detail) + "\n"
+}
+
+// Indent returns the text of err with detail, as printed by Format, with
+// prefix added at the start of each line, including the first, for
+// embedding it in a larger text, such as under a bullet point. Line breaks in
+// messages also start new lines. The trailing line break or indentation
+// left by the detail of the innermost error, if any, is removed.
+func Indent(err error, prefix string) string {
+	s := strings.TrimSuffix(Format(err, true), string(detailSep))
+	s = strings.TrimSuffix(s, "\n")
+	return prefix + strings.ReplaceAll(s, "\n", "\n"+prefix)
+}
+
+// Compact returns the text of err on a single line without frames or detail,
+// for uses that need a stable text, such as metric labels. The messages of
+// the errors of the chain, as described for Causes, are joined with ": ",
+// and the chains of the errors joined by Join are joined with "; ". Any other
+// error that wraps several errors, such as one returned by Errorf with
+// several %w verbs, contributes its own message, which includes theirs. Line
+// breaks in messages are replaced by spaces, and messages longer than the
+// limit set with SetMaxNodeMessageLen, if any, are cut as when printing with
+// detail.
+//
+// Compact ignores the options set with SetRenderOptions, except in the
+// messages of errors that print other errors with Format themselves.
+func Compact(err error) string {
+	if err == nil {
+		return "<nil>"
+	}
+	var w walker
+	var b strings.Builder
+	w.compact(&b, err)
+	return b.String()
+}
+
+var lineBreaks = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ")
+
+func (w *walker) compact(b *strings.Builder, err error) {
+	for sep := ""; err != nil && !w.visited(err); sep = ": " {
+		b.WriteString(sep)
+		if j, ok := err.(*joinError); ok {
+			for i, err := range j.errs {
+				if i > 0 {
+					b.WriteString("; ")
+				}
+				w.compact(b, err)
+			}
+			return
+		}
+		var msg string
+		msg, err = link(err)
+		b.WriteString(truncate(lineBreaks.Replace(msg), maxNodeMessageLen))
+	}
+}
+
+// truncate cuts s after n runes, followed by an ellipsis, if n is positive
+// and s is longer.
+func truncate(s string, n int) string {
+	if n <= 0 {
+		return s
+	}
+	for i := range s {
+		if n == 0 {
+			return s[:i] + "…"
+		}
+		n--
+	}
+	return s
+}
+
+// formatInPlace formats err as if it were the error being formatted. It is
+// used by errors that do not contribute a message of their own.
+func formatInPlace(p Printer, err error) (next error) {
+	if f, ok := err.(Formatter); ok {
+		return f.Format(p)
+	}
+	p.Print(err)
+	return nil
+}
+
+// message returns the message contributed by err itself, excluding
+// the errors it wraps and any detail.
+func message(err error) string {
+	p := &printer{}
+	switch v := err.(type) {
+	case Formatter:
+		v.Format(p)
+	case interface{ FormatError(Printer) error }:
+		v.FormatError(p)
+	default:
+		return err.Error()
+	}
+	return string(p.buf)
+}
+
+// messages returns the messages of the errors that make up the text of err,
+// outermost first, so that joining them with ": " yields Format(err, false).
+//
+// A Formatter contributes the message it prints. Any other error contributes
+// its Error text, without the text of the error it wraps if it ends with it.
+// The walk stops at an error that wraps several errors, in which case multi
+// is set and that error does not contribute a message.
+func messages(err error) (msgs []string, multi bool) {
+	var w walker
+	for err != nil && !w.visited(err) {
+		if _, ok := err.(interface{ Unwrap() []error }); ok {
+			return msgs, true
+		}
+		var msg string
+		msg, err = link(err)
+		msgs = append(msgs, msg)
+	}
+	return msgs, false
+}
+
+// link returns the message contributed by err, as described for messages,
+// and the next error of the chain whose message is printed after it.
+func link(err error) (msg string, next error) {
+	p := &printer{}
+	switch v := err.(type) {
+	case Formatter:
+		next = v.Format(p)
+		return string(p.buf), next
+	case interface{ FormatError(Printer) error }:
+		next = v.FormatError(p)
+		return string(p.buf), next
+	}
+	msg = err.Error()
+	if next = Unwrap(err); next != nil {
+		if s := ": " + next.Error(); strings.HasSuffix(msg, s) {
+			return msg[:len(msg)-len(s)], next
+		}
+	}
+	return msg, nil
+}
+
+// detailIndent is the indentation of detail, set with SetDetailIndent, and
+// detailSep separates its lines.
+var (
+	detailIndent = "    "
+	detailSep    = []byte("\n    ")
+)
+
+// printer implements Printer, writing to a buffer. It also implements
+// fmt.State for errors that implement fmt.Formatter.
+type printer struct {
+	buf  []byte
+	opts RenderOptions
+
+	// detail reports whether detail was requested.
+	detail bool
+	// inDetail is set once the error being printed has called Detail.
+	inDetail bool
+	// msgEnd is the end of the message of the error being printed in buf,
+	// recorded when it calls Detail.
+	msgEnd int
+	// indent reports whether new lines must be indented.
+	indent bool
+
+	// w, if set, receives the text as the errors are printed; n counts the
+	// bytes written to it and werr records the first write error.
+	w    io.Writer
+	n    int
+	werr error
+}
+
+// format prints the chain of err.
+func (p *printer) format(err error) {
+	sep := p.opts.Separator // separator before next error
+	switch {
+	case p.detail:
+		sep = "\n--- "
+	case sep == "":
+		sep = ": "
+	}
+
+loop:
+	for {
+		p.inDetail = false
+		node := err
+		start := len(p.buf)
+		err = p.formatNode(err)
+		if p.detail && maxNodeMessageLen > 0 {
+			p.truncateMessage(start)
+		}
+		if p.opts.ShortFrame && !p.detail {
+			p.shortFrame(node)
+		}
+		if err == nil {
+			break loop
+		}
+		if p.detail && !p.inDetail {
+			p.buf = append(p.buf, ':')
+		}
+		// Strip last newline of detail.
+		p.buf = bytes.TrimSuffix(p.buf, detailSep)
+		p.buf = append(p.buf, sep...)
+		if !p.flush() {
+			return
+		}
+	}
+	p.flush()
+}
+
+// flush writes the buffer to w, if set, and empties it. It reports whether
+// printing may go on.
+func (p *printer) flush() bool {
+	if p.w == nil {
+		return true
+	}
+	n, err := p.w.Write(p.buf)
+	p.n += n
+	p.buf = p.buf[:0]
+	if err != nil {
+		p.werr = err
+		return false
+	}
+	return true
+}
+
+// formatNode prints the message and detail of err alone and returns the next
+// error to print, if any.
+func (p *printer) formatNode(err error) (next error) {
+	switch v := err.(type) {
+	case Formatter:
+		return v.Format(p)
+	// This case is for supporting old error implementations, which can be
+	// migrated with FormatErrorAdapter. It may eventually disappear.
+	case interface{ FormatError(Printer) error }:
+		return v.FormatError(p)
+	case fmt.Formatter:
+		// Setting the plus flag signals a request for detail, if
+		// interpreted as %+v.
+		v.Format(p, 'v')
+	default:
+		p.buf = append(p.buf, v.Error()...)
+	}
+	return nil
+}
+
+// truncateMessage shortens the message of the error printed from start, if
+// it has more than maxNodeMessageLen runes.
+func (p *printer) truncateMessage(start int) {
+	end := len(p.buf)
+	if p.inDetail {
+		end = p.msgEnd
+	}
+	msg := p.buf[start:end]
+	i, n := 0, 0
+	for ; i < len(msg) && n < maxNodeMessageLen; n++ {
+		_, size := utf8.DecodeRune(msg[i:])
+		i += size
+	}
+	if i == len(msg) {
+		return
+	}
+	rest := append([]byte("…"), p.buf[end:]...)
+	p.buf = append(p.buf[:start+i], rest...)
+}
+
+// shortFrame prints the file base name and line of the frame of err, if any.
+func (p *printer) shortFrame(err error) {
+	f, ok := err.(Framer)
+	if !ok {
+		return
+	}
+	if _, file, line := f.Frame().Location(); file != "" {
+		p.buf = append(p.buf, fmt.Sprintf(" (%s:%d)", filepath.Base(file), line)...)
+	}
+}
+
+func (p *printer) Print(args ...interface{}) {
+	if !p.inDetail || p.detail {
+		p.write(internal.Sprint(args...))
+	}
+}
+
+func (p *printer) Printf(format string, args ...interface{}) {
+	if !p.inDetail || p.detail {
+		p.write(internal.Sprintf(format, args...))
+	}
+}
+
+func (p *printer) Detail() bool {
+	inDetail := p.inDetail
+	if !inDetail {
+		p.msgEnd = len(p.buf)
+	}
+	p.inDetail = true
+	p.indent = p.detail
+	if p.detail && !inDetail {
+		p.write(":\n")
+	}
+	return p.detail
+}
+
+// write appends s to the buffer, indenting new lines if needed.
+func (p *printer) write(s string) {
+	if !p.indent {
+		p.buf = append(p.buf, s...)
+		return
+	}
+	k := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\n' {
+			p.buf = append(p.buf, s[k:i]...)
+			p.buf = append(p.buf, detailSep...)
+			k = i + 1
+		}
+	}
+	p.buf = append(p.buf, s[k:]...)
+}
+
+func (p *printer) Write(b []byte) (n int, err error) {
+	if !p.inDetail || p.detail {
+		p.write(string(b))
+	}
+	return len(b), nil
+}
+
+func (p *printer) Width() (wid int, ok bool)      { return 0, false }
+func (p *printer) Precision() (prec int, ok bool) { return 0, false }
+func (p *printer) Flag(c int) bool                { return c == '+' && p.detail }
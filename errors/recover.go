@@ -0,0 +1,128 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// maxPanicFrames is the maximum number of PCs recorded by RecoverStack.
+const maxPanicFrames = 64
+
+// RecoverStack returns an error describing the panic value r together with
+// the stack of the panicking goroutine. It returns nil if r is nil. If r is
+// an error, the returned error wraps it.
+//
+// RecoverStack must be called from a deferred function, typically as
+//
+//	defer func() {
+//		if r := recover(); r != nil {
+//			err = errors.RecoverStack(r)
+//		}
+//	}()
+//
+// While a deferred function runs during a panic, the stack still contains
+// the frames that led to the panic, below the frames of the deferred call
+// and of the runtime's panic machinery. When the error is printed with
+// detail, RecoverStack skips every frame up to and including runtime.gopanic,
+// as well as any runtime frames that immediately follow it (such as
+// runtime.panicmem and runtime.sigpanic for run-time errors), so that the
+// first frame printed is the one that called panic or caused the fault.
+// If no runtime.gopanic frame is found, for instance because RecoverStack
+// was not called during a panic, the stack is printed from the caller of
+// RecoverStack.
+func RecoverStack(r interface{}) error {
+	if r == nil {
+		return nil
+	}
+	pcs := make([]uintptr, maxPanicFrames)
+	// Skip runtime.Callers and RecoverStack.
+	n := runtime.Callers(2, pcs)
+	return &panicError{val: r, pcs: pcs[:n:n]}
+}
+
+// panicError is an error created from a recovered panic.
+type panicError struct {
+	val interface{}
+	pcs []uintptr
+}
+
+func (e *panicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.val)
+}
+
+func (e *panicError) Format(p Printer) (next error) {
+	p.Print("panic: ", e.val)
+	if p.Detail() {
+		for _, fr := range e.stack() {
+			p.Printf("%s\n%s%s:%d\n", fr.Function, detailIndent, fr.File, fr.Line)
+		}
+	}
+	return nil
+}
+
+// Unwrap returns the panic value if it is an error, so that Is and As match
+// the errors that were passed to panic.
+func (e *panicError) Unwrap() error {
+	err, _ := e.val.(error)
+	return err
+}
+
+// PanicValue returns the value passed to panic. It marks the errors created
+// from panics for IsPanic and PanicValue.
+func (e *panicError) PanicValue() interface{} { return e.val }
+
+// stack returns the frames of the panicking goroutine, starting at the
+// origin of the panic.
+func (e *panicError) stack() []runtime.Frame {
+	var all []runtime.Frame
+	frames := runtime.CallersFrames(e.pcs)
+	for {
+		fr, more := frames.Next()
+		all = append(all, fr)
+		if !more {
+			break
+		}
+	}
+	for i, fr := range all {
+		if fr.Function != "runtime.gopanic" {
+			continue
+		}
+		i++
+		for i < len(all) && strings.HasPrefix(all[i].Function, "runtime.") {
+			i++
+		}
+		return all[i:]
+	}
+	return all
+}
+
+// IsPanic reports whether err's chain has an error created from a recovered
+// panic, such as by RecoverStack, for instance to report the panics of
+// goroutines differently from the errors they return.
+func IsPanic(err error) bool {
+	_, ok := PanicValue(err)
+	return ok
+}
+
+// PanicValue returns the value passed to panic of the outermost error in
+// err's chain created from a recovered panic, and reports whether there is
+// one. An error is created from a recovered panic, as are the errors returned
+// by RecoverStack, if it implements
+//
+//	interface { PanicValue() interface{} }
+func PanicValue(err error) (v interface{}, ok bool) {
+	Walk(err, func(err error) bool {
+		if !ok {
+			if e, isPanic := err.(interface{ PanicValue() interface{} }); isPanic {
+				v, ok = e.PanicValue(), true
+			}
+		}
+		return !ok
+	})
+	return v, ok
+}
@@ -0,0 +1,105 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/fmt"
+)
+
+func panicky() {
+	panic("boom")
+}
+
+func recoverFrom(f func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.RecoverStack(r)
+		}
+	}()
+	f()
+	return nil
+}
+
+func TestRecoverStack(t *testing.T) {
+	if err := errors.RecoverStack(nil); err != nil {
+		t.Errorf("RecoverStack(nil) = %v, want nil", err)
+	}
+
+	err := recoverFrom(panicky)
+	if got, want := err.Error(), "panic: boom"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	lines := strings.Split(fmt.Sprintf("%+v", err), "\n")
+	if len(lines) < 3 {
+		t.Fatalf("%%+v: got %q; want message and stack", lines)
+	}
+	if got, want := lines[0], "panic: boom:"; got != want {
+		t.Errorf("first line: got %q; want %q", got, want)
+	}
+	if got, want := strings.TrimSpace(lines[1]), "golang.org/x/exp/errors_test.panicky"; got != want {
+		t.Errorf("top frame: got %q; want %q", got, want)
+	}
+	if !strings.Contains(lines[2], "recover_test.go:") {
+		t.Errorf("top frame location: got %q; want recover_test.go", lines[2])
+	}
+}
+
+func TestRecoverStackRuntimeError(t *testing.T) {
+	err := recoverFrom(func() {
+		var m map[string]int
+		m["x"] = 1
+	})
+	lines := strings.Split(fmt.Sprintf("%+v", err), "\n")
+	if len(lines) < 2 {
+		t.Fatalf("%%+v: got %q; want message and stack", lines)
+	}
+	if got := strings.TrimSpace(lines[1]); strings.HasPrefix(got, "runtime.") {
+		t.Errorf("top frame: got %q; want non-runtime frame", got)
+	}
+}
+
+func TestRecoverStackError(t *testing.T) {
+	err := recoverFrom(func() { panic(fmt.Errorf("read: %v", io.EOF)) })
+	if !errors.Is(err, io.EOF) {
+		t.Errorf("Is(recovered, io.EOF) = false, want true")
+	}
+	if got, want := err.Error(), "panic: read: EOF"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if err := recoverFrom(panicky); errors.Unwrap(err) != nil {
+		t.Errorf("Unwrap(recovered string) = %v, want nil", errors.Unwrap(err))
+	}
+}
+
+func TestPanicValue(t *testing.T) {
+	errPanic := recoverFrom(panicky)
+	wrapped := errors.WithStatus(fmt.Errorf("worker: %v", errPanic), 500)
+	testCases := []struct {
+		err error
+		v   interface{}
+		ok  bool
+	}{
+		{nil, nil, false},
+		{io.EOF, nil, false},
+		{errPanic, "boom", true},
+		{wrapped, "boom", true},
+		{errors.Join(io.EOF, wrapped), "boom", true},
+		{recoverFrom(func() { panic(io.EOF) }), io.EOF, true},
+	}
+	for _, tc := range testCases {
+		v, ok := errors.PanicValue(tc.err)
+		if v != tc.v || ok != tc.ok {
+			t.Errorf("PanicValue(%v) = %v, %v; want %v, %v", tc.err, v, ok, tc.v, tc.ok)
+		}
+		if got := errors.IsPanic(tc.err); got != tc.ok {
+			t.Errorf("IsPanic(%v) = %v, want %v", tc.err, got, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+// Redact returns an error that formats as publicMsg only, even when printed
+// with detail: it shows neither the message, the frames, nor the chain of
+// err. It is intended for errors that cross a trust boundary, such as errors
+// returned to clients.
+//
+// The returned error unwraps to err, so Is and As still match the original
+// chain. Use Internal to retrieve err for logging. The functions that expose
+// the text or frames of the errors of a chain, namely StackTrace, SprintStack,
+// OTelAttributes, Contains and Fingerprint, stop at the returned error, as
+// they do at an error returned by Opaque, so that the returned error reveals
+// nothing of err through them either. Redact returns nil if err is nil.
+func Redact(err error, publicMsg string) error {
+	if err == nil {
+		return nil
+	}
+	return &redacted{publicMsg, err}
+}
+
+type redacted struct {
+	msg string
+	err error
+}
+
+func (e *redacted) Error() string { return e.msg }
+
+func (e *redacted) Format(p Printer) (next error) {
+	p.Print(e.msg)
+	return nil
+}
+
+func (e *redacted) Unwrap() error { return e.err }
+
+// isRedacted reports whether err was returned by Redact, so that the
+// functions exposing the errors of a chain do not look past it.
+func isRedacted(err error) bool {
+	_, ok := err.(*redacted)
+	return ok
+}
+
+func (e *redacted) CloneWrapping(errs []error) error {
+	c := *e
+	c.err = errs[0]
+	return &c
+}
+
+// Internal returns the error hidden by the outermost error in err's chain
+// that was created by Redact, or err itself if there is none.
+func Internal(err error) error {
+	var r *redacted
+	if As(err, &r) {
+		return r.err
+	}
+	return err
+}
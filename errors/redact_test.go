@@ -0,0 +1,59 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors_test
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/fmt"
+)
+
+func TestRedact(t *testing.T) {
+	secret := errors.New("password for db01 rejected")
+	err := errors.Redact(fmt.Errorf("query failed: %v", secret), "internal error")
+
+	for _, f := range []string{"%v", "%+v", "%s", "%q"} {
+		got := fmt.Sprintf(f, err)
+		if strings.Contains(got, "db01") || strings.Contains(got, ".go") {
+			t.Errorf("Sprintf(%q, err) = %q; leaks internal information", f, got)
+		}
+	}
+	if got, want := fmt.Sprintf("%+v", err), "internal error"; got != want {
+		t.Errorf("%%+v: got %q; want %q", got, want)
+	}
+	if !errors.Is(err, secret) {
+		t.Errorf("Is(err, secret) = false, want true")
+	}
+	if got := errors.Internal(err); errors.Unwrap(got) != secret {
+		t.Errorf("Internal(err) = %v, want error wrapping %v", got, secret)
+	}
+	if got := errors.Internal(secret); got != secret {
+		t.Errorf("Internal(secret) = %v, want %v", got, secret)
+	}
+
+	outer := fmt.Errorf("handler: %w", err)
+	if got := errors.StackTrace(outer); len(got) != 1 {
+		t.Errorf("StackTrace(outer) has %d frames, want 1 of outer only", len(got))
+	}
+	if got := errors.SprintStack(err); got != "" {
+		t.Errorf("SprintStack(err) = %q, want \"\"", got)
+	}
+	if typ, _, stack := errors.OTelAttributes(err); typ != "*errors.redacted" || stack != "" {
+		t.Errorf("OTelAttributes(err) = %q, _, %q; want *errors.redacted, _, \"\"", typ, stack)
+	}
+	if errors.Contains(outer, "db01") || !errors.Contains(outer, "internal error") {
+		t.Errorf("Contains(outer, ...) looks past the redacted error")
+	}
+	other := errors.Redact(errors.New("disk full"), "internal error")
+	if !errors.SameFingerprint(err, other) {
+		t.Errorf("SameFingerprint of redacted errors with different chains = false, want true")
+	}
+
+	if got := errors.Redact(nil, "x"); got != nil {
+		t.Errorf("Redact(nil) = %v, want nil", got)
+	}
+}
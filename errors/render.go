@@ -0,0 +1,58 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import "strings"
+
+// RenderOptions control the output of Format without detail. As the %v verb
+// of package errors/fmt and the Error methods of the errors of this package
+// are implemented with Format, they control the text of errors program-wide.
+//
+// The zero value renders errors as "outer: inner".
+type RenderOptions struct {
+	// Separator is printed between the messages of a chain.
+	// The empty string means ": ".
+	Separator string
+
+	// ShortFrame adds the file base name and line of the errors that record
+	// their location after their message, as in "outer (main.go:12)".
+	ShortFrame bool
+}
+
+var renderOptions RenderOptions
+
+// SetRenderOptions sets the options used when formatting errors without
+// detail. It affects the %v output of all errors in the program, so it
+// should be called early, for instance at the start of main, and not
+// concurrently with formatting errors.
+func SetRenderOptions(opts RenderOptions) {
+	renderOptions = opts
+}
+
+var maxNodeMessageLen int
+
+// SetMaxNodeMessageLen limits the message of each error printed with detail
+// to n runes: a longer message is cut after n runes and followed by an
+// ellipsis, so that the chain remains readable if one of its errors has a
+// huge message. The messages of the errors themselves, as returned by their
+// Error methods, are unaffected. An n of 0 or less, the default, removes
+// the limit. Like SetRenderOptions, it should be called early and not
+// concurrently with formatting errors.
+func SetMaxNodeMessageLen(n int) {
+	maxNodeMessageLen = n
+}
+
+// SetDetailIndent sets the string used for each level of indentation of the
+// detail of errors printed with detail, which is four spaces by default.
+// It panics if indent contains a line break. Like SetRenderOptions, it
+// affects all errors in the program, so it should be called early and not
+// concurrently with formatting errors.
+func SetDetailIndent(indent string) {
+	if strings.ContainsAny(indent, "\r\n") {
+		panic("errors: detail indentation contains a line break")
+	}
+	detailIndent = indent
+	detailSep = []byte("\n" + indent)
+}
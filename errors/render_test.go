@@ -0,0 +1,116 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors_test
+
+import (
+	"os"
+	"regexp"
+	"testing"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/fmt"
+)
+
+func TestRenderOptions(t *testing.T) {
+	defer errors.SetRenderOptions(errors.RenderOptions{})
+
+	err := fmt.Errorf("high: %v", fmt.Errorf("mid: %v", errors.New("low")))
+	testCases := []struct {
+		opts errors.RenderOptions
+		want string
+	}{
+		{errors.RenderOptions{}, `^high: mid: low$`},
+		{errors.RenderOptions{Separator: " -> "}, `^high -> mid -> low$`},
+		{errors.RenderOptions{ShortFrame: true},
+			`^high \(render_test.go:\d+\): mid \(render_test.go:\d+\): low \(render_test.go:\d+\)$`},
+	}
+	for _, tc := range testCases {
+		errors.SetRenderOptions(tc.opts)
+		for _, got := range []string{err.Error(), fmt.Sprint(err), errors.Format(err, false)} {
+			if !regexp.MustCompile(tc.want).MatchString(got) {
+				t.Errorf("%+v: got %q; want match for %s", tc.opts, got, tc.want)
+			}
+		}
+		// Detail is not affected.
+		errors.SetRenderOptions(errors.RenderOptions{})
+		want := fmt.Sprintf("%+v", err)
+		errors.SetRenderOptions(tc.opts)
+		if got := fmt.Sprintf("%+v", err); got != want {
+			t.Errorf("%+v: %%+v = %q, want %q", tc.opts, got, want)
+		}
+	}
+
+	errors.SetRenderOptions(errors.RenderOptions{ShortFrame: true})
+	if got := errors.New("leaf").Error(); !regexp.MustCompile(`^leaf \(render_test.go:\d+\)$`).MatchString(got) {
+		t.Errorf("New(\"leaf\").Error() = %q, want frame", got)
+	}
+}
+
+func TestSetDetailIndent(t *testing.T) {
+	defer errors.SetDetailIndent("    ")
+
+	errors.SetDetailIndent("\t")
+	err := fmt.Errorf("wrap: %v", errorD{})
+	got := fmt.Sprintf("%+v", err)
+	re := regexp.MustCompile(`^wrap:\n\tgolang.org/x/exp/errors_test.TestSetDetailIndent\n\t\t.*render_test.go:\d+\n--- errorD:\n\tdetail$`)
+	if !re.MatchString(got) {
+		t.Errorf("%%+v: got %q; want match for %s", got, re)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("SetDetailIndent(\"\\n\"): no panic")
+		}
+	}()
+	errors.SetDetailIndent("\n")
+}
+
+func TestSetMaxNodeMessageLen(t *testing.T) {
+	defer errors.SetMaxNodeMessageLen(0)
+
+	err := fmt.Errorf("héllo wörld: %v", fmt.Errorf("short: %v", errorD{}))
+	errors.SetMaxNodeMessageLen(6)
+	got := fmt.Sprintf("%+v", err)
+	re := regexp.MustCompile(`^héllo …:\n    .*\n        .*\n--- short:\n    .*\n        .*\n--- errorD:\n    detail$`)
+	if !re.MatchString(got) {
+		t.Errorf("%%+v: got %q; want match for %s", got, re)
+	}
+	if got, want := err.Error(), "héllo wörld: short: errorD"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if got, want := fmt.Sprintf("%+v", errorT{}), "errorT"; got != want {
+		t.Errorf("%%+v of errorT = %q, want %q", got, want)
+	}
+}
+
+func TestCompact(t *testing.T) {
+	defer errors.SetRenderOptions(errors.RenderOptions{})
+	defer errors.SetMaxNodeMessageLen(0)
+
+	testCases := []struct {
+		err  error
+		want string
+	}{
+		{nil, "<nil>"},
+		{fmt.Errorf("high: %v", fmt.Errorf("mid: %v", errors.New("low"))), "high: mid: low"},
+		{errors.WithStatus(fmt.Errorf("two\nlines: %v", errorT{}), 404), "two lines: errorT"},
+		{fmt.Errorf("op: %v", errors.Join(errors.New("a"), fmt.Errorf("b: %v", errorT{}))), "op: a; b: errorT"},
+		{&os.PathError{Op: "open", Path: "f", Err: errorT{}}, "open f: errorT"},
+		{fmt.Errorf("copy %w to %w", errors.New("e1"), errors.New("e2")), "copy e1 to e2"},
+		{fmt.Errorf("op: %w", fmt.Errorf("copy %w to %w", errorT{}, errors.Join(errors.New("a"), errors.New("b")))), "op: copy errorT to a; b"},
+	}
+	errors.SetRenderOptions(errors.RenderOptions{Separator: " <- ", ShortFrame: true})
+	for _, tc := range testCases {
+		if got := errors.Compact(tc.err); got != tc.want {
+			t.Errorf("Compact(%+v) = %q, want %q", tc.err, got, tc.want)
+		}
+	}
+
+	errors.SetMaxNodeMessageLen(3)
+	err := fmt.Errorf("héllo: %v", errors.New("wörld"))
+	if got, want := errors.Compact(err), "hél…: wör…"; got != want {
+		t.Errorf("Compact with limit = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import "time"
+
+// WithRetryAfter returns an error that annotates err with the duration after
+// which the failed operation may be retried. The returned error formats as
+// err, followed by the duration when printed with detail, and unwraps to err.
+// WithRetryAfter returns nil if err is nil.
+func WithRetryAfter(err error, d time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	return &withRetryAfter{err, d}
+}
+
+type withRetryAfter struct {
+	err error
+	d   time.Duration
+}
+
+func (e *withRetryAfter) Error() string { return e.err.Error() }
+
+func (e *withRetryAfter) Format(p Printer) (next error) {
+	next = formatInPlace(p, e.err)
+	if p.Detail() {
+		p.Printf("retry after %v\n", e.d)
+	}
+	return next
+}
+
+func (e *withRetryAfter) Unwrap() error { return e.err }
+
+func (e *withRetryAfter) CloneWrapping(errs []error) error {
+	c := *e
+	c.err = errs[0]
+	return &c
+}
+
+// RetryAfter returns the outermost duration attached to err's chain with
+// WithRetryAfter. It reports false if there is none.
+func RetryAfter(err error) (time.Duration, bool) {
+	var r *withRetryAfter
+	if As(err, &r) {
+		return r.d, true
+	}
+	return 0, false
+}
@@ -0,0 +1,53 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors_test
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/fmt"
+)
+
+func TestRetryAfter(t *testing.T) {
+	err1 := errors.New("1")
+	testCases := []struct {
+		err  error
+		want time.Duration
+		ok   bool
+	}{
+		{nil, 0, false},
+		{err1, 0, false},
+		{errors.WithRetryAfter(err1, time.Second), time.Second, true},
+		{fmt.Errorf("wrap: %v", errors.WithRetryAfter(err1, time.Second)), time.Second, true},
+		{errors.WithRetryAfter(errors.WithRetryAfter(err1, time.Second), time.Minute), time.Minute, true},
+	}
+	for _, tc := range testCases {
+		if got, ok := errors.RetryAfter(tc.err); got != tc.want || ok != tc.ok {
+			t.Errorf("RetryAfter(%v) = %v, %v; want %v, %v", tc.err, got, ok, tc.want, tc.ok)
+		}
+	}
+	if err := errors.WithRetryAfter(nil, time.Second); err != nil {
+		t.Errorf("WithRetryAfter(nil, 1s) = %v, want nil", err)
+	}
+}
+
+func TestWithRetryAfterFormat(t *testing.T) {
+	testCases := []struct {
+		err  error
+		verb string
+		want string
+	}{
+		{errors.WithRetryAfter(errorT{}, time.Second), "%v", "errorT"},
+		{errors.WithRetryAfter(errorT{}, time.Second), "%+v", "errorT:\n    retry after 1s\n    "},
+		{fmt.Errorf("wrap: %v", errors.WithRetryAfter(errorT{}, 5*time.Second)), "%v", "wrap: errorT"},
+	}
+	for _, tc := range testCases {
+		if got := fmt.Sprintf(tc.verb, tc.err); got != tc.want {
+			t.Errorf("%s: got %q; want %q", tc.verb, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"context"
+	"os"
+)
+
+// Sentinel errors for conditions common to many packages. Libraries should
+// prefer them to sentinels of their own for conditions that callers classify
+// across packages, so that a single test with Is recognizes the condition
+// whatever the package reporting it.
+//
+// Where the standard library has equivalent errors, as noted, the sentinels
+// match them both ways: Is(context.Canceled, ErrCanceled) and
+// Is(ErrCanceled, context.Canceled) are both true.
+var (
+	// ErrUnsupported reports that an operation is not supported.
+	ErrUnsupported error = &condition{msg: "unsupported operation"}
+
+	// ErrNotImplemented reports that an operation is not implemented yet.
+	ErrNotImplemented error = &condition{msg: "not implemented"}
+
+	// ErrTimeout reports that an operation timed out. It matches
+	// context.DeadlineExceeded, os.ErrDeadlineExceeded and any error with a
+	// method Timeout() bool returning true.
+	ErrTimeout error = &condition{
+		msg:     "timeout",
+		std:     []error{context.DeadlineExceeded, os.ErrDeadlineExceeded},
+		timeout: true,
+	}
+
+	// ErrCanceled reports that an operation was canceled. It matches
+	// context.Canceled.
+	ErrCanceled error = &condition{msg: "canceled", std: []error{context.Canceled}}
+)
+
+// A condition is a sentinel error for a common condition, which matches the
+// equivalent errors of the standard library. Being a Target, it is matched
+// by the errors that it accepts rather than only by itself.
+type condition struct {
+	msg     string
+	std     []error // equivalent errors of the standard library
+	timeout bool    // whether errors reporting a timeout are equivalent
+}
+
+func (e *condition) Error() string { return e.msg }
+
+func (e *condition) Format(p Printer) (next error) {
+	p.Print(e.msg)
+	return nil
+}
+
+// Is reports whether target is an equivalent error of the standard library.
+func (e *condition) Is(target error) bool {
+	for _, std := range e.std {
+		if target == std {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchError reports whether err is e, an equivalent error or an error whose
+// Is method reports that it matches e.
+func (e *condition) MatchError(err error) bool {
+	if err == e || e.Is(err) {
+		return true
+	}
+	if t, ok := err.(interface{ Timeout() bool }); ok && e.timeout && t.Timeout() {
+		return true
+	}
+	x, ok := err.(interface{ Is(error) bool })
+	return ok && x.Is(e)
+}
@@ -0,0 +1,60 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors_test
+
+import (
+	"context"
+	"net"
+	"os"
+	"testing"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/fmt"
+)
+
+// timeoutErr reports a timeout.
+type timeoutErr struct{}
+
+func (timeoutErr) Error() string { return "timed out" }
+func (timeoutErr) Timeout() bool { return true }
+
+// unsupportedErr matches ErrUnsupported with its Is method.
+type unsupportedErr struct{}
+
+func (unsupportedErr) Error() string        { return "no such feature" }
+func (unsupportedErr) Is(target error) bool { return target == errors.ErrUnsupported }
+
+func TestSentinels(t *testing.T) {
+	testCases := []struct {
+		err, target error
+		want        bool
+	}{
+		{fmt.Errorf("read: %v", errors.ErrUnsupported), errors.ErrUnsupported, true},
+		{fmt.Errorf("read: %v", unsupportedErr{}), errors.ErrUnsupported, true},
+		{errors.ErrUnsupported, errors.ErrNotImplemented, false},
+		{errors.ErrNotImplemented, errors.ErrNotImplemented, true},
+		{fmt.Errorf("call: %v", context.Canceled), errors.ErrCanceled, true},
+		{errors.ErrCanceled, context.Canceled, true},
+		{fmt.Errorf("call: %v", context.DeadlineExceeded), errors.ErrTimeout, true},
+		{&net.OpError{Op: "read", Err: os.ErrDeadlineExceeded}, errors.ErrTimeout, true},
+		{timeoutErr{}, errors.ErrTimeout, true},
+		{errors.ErrTimeout, context.DeadlineExceeded, true},
+		{context.Canceled, errors.ErrTimeout, false},
+		{errors.New("canceled"), errors.ErrCanceled, false},
+	}
+	for _, tc := range testCases {
+		if got := errors.Is(tc.err, tc.target); got != tc.want {
+			t.Errorf("Is(%v, %v) = %v, want %v", tc.err, tc.target, got, tc.want)
+		}
+	}
+}
+
+func TestSentinelsFormat(t *testing.T) {
+	for _, err := range []error{errors.ErrUnsupported, errors.ErrNotImplemented, errors.ErrTimeout, errors.ErrCanceled} {
+		if got, want := fmt.Sprintf("%+v", err), err.Error(); got != want {
+			t.Errorf("%%+v: got %q; want %q", got, want)
+		}
+	}
+}
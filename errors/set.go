@@ -0,0 +1,93 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import "reflect"
+
+// A Set is a fixed set of target errors, optimized for the common case of
+// checking that an error matches none of them.
+//
+// A Set keeps a small Bloom filter of the targets that are pointers, which
+// covers all errors created with New. Other targets, including those that
+// implement Target, are checked one by one.
+type Set struct {
+	bloom   [4]uint64 // 256 bits
+	targets []error
+	others  bool // some targets are not pointers
+}
+
+// NewSet returns a Set of the given targets. Nil targets are ignored.
+func NewSet(targets ...error) *Set {
+	s := &Set{}
+	for _, t := range targets {
+		if t == nil {
+			continue
+		}
+		s.targets = append(s.targets, t)
+		if _, ok := t.(Target); ok {
+			s.others = true
+		} else if p, ok := pointer(t); ok {
+			h1, h2 := bloomHash(p)
+			s.bloom[h1/64] |= 1 << (h1 % 64)
+			s.bloom[h2/64] |= 1 << (h2 % 64)
+		} else {
+			s.others = true
+		}
+	}
+	return s
+}
+
+// MayContain reports whether any error in err's chain matches one of the
+// targets of s.
+//
+// The chain is first tested against the filter, which never reports false
+// negatives. Chains with errors that may match a target other than by being
+// equal to it, such as errors with an Is method or errors of other packages,
+// always pass the filter. If the filter reports a possible match, the result
+// is confirmed with Is, so a true result means that Is(err, target) is true
+// for some target.
+func (s *Set) MayContain(err error) bool {
+	if !s.others && !s.mayContain(err) {
+		return false
+	}
+	for _, t := range s.targets {
+		if isChain(err, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// mayContain reports whether err's chain may match a target of s: whether
+// any pointer in it passes the filter, or it has errors that may match a
+// target other than by being equal to it, as determined by plainMatch.
+func (s *Set) mayContain(err error) bool {
+	may := false
+	Walk(err, func(err error) bool {
+		if !plainMatch(err) {
+			may = true
+		} else if p, ok := pointer(err); ok {
+			h1, h2 := bloomHash(p)
+			may = s.bloom[h1/64]&(1<<(h1%64)) != 0 && s.bloom[h2/64]&(1<<(h2%64)) != 0
+		}
+		return !may
+	})
+	return may
+}
+
+// pointer returns the address held by err if its dynamic type is a pointer.
+func pointer(err error) (uintptr, bool) {
+	v := reflect.ValueOf(err)
+	if v.Kind() != reflect.Ptr {
+		return 0, false
+	}
+	return v.Pointer(), true
+}
+
+// bloomHash returns two bit positions in a 256-bit filter for the address p.
+func bloomHash(p uintptr) (h1, h2 uint) {
+	h := uint64(p) * 0x9E3779B97F4A7C15
+	return uint(h>>56) & 255, uint(h>>48) & 255
+}
@@ -0,0 +1,87 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors_test
+
+import (
+	"strconv"
+	"testing"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/fmt"
+)
+
+// isErr is an error that matches target with its Is method.
+type isErr struct{ target error }
+
+func (e isErr) Error() string { return "isErr" }
+
+func (e isErr) Is(target error) bool { return target == e.target }
+
+func TestSet(t *testing.T) {
+	err1 := errors.New("1")
+	err2 := errors.New("2")
+	err3 := errors.New("3")
+	set := errors.NewSet(err1, nil, err2)
+	valueSet := errors.NewSet(err3, errorT{})
+
+	testCases := []struct {
+		set   *errors.Set
+		err   error
+		match bool
+	}{
+		{set, nil, false},
+		{set, err1, true},
+		{set, err2, true},
+		{set, err3, false},
+		{set, fmt.Errorf("wrap: %v", err2), true},
+		{set, fmt.Errorf("wrap: %v", err3), false},
+		{set, errors.Opaque(err1), false},
+		{set, errors.Join(err3, err1), true},
+		{set, fmt.Errorf("wrap: %v", errors.Join(err3, fmt.Errorf("b: %v", err2))), true},
+		{set, errors.Join(err3, errorT{}), false},
+		{set, fmt.Errorf("wrap: %v", errorIs("1")), true},
+		{set, fmt.Errorf("wrap: %v", isErr{err2}), true},
+		{set, fmt.Errorf("wrap: %v", isErr{err3}), false},
+		{valueSet, fmt.Errorf("wrap: %v", errorT{}), true},
+		{valueSet, err1, false},
+		{errors.NewSet(), err1, false},
+	}
+	for _, tc := range testCases {
+		if got := tc.set.MayContain(tc.err); got != tc.match {
+			t.Errorf("MayContain(%v) = %v, want %v", tc.err, got, tc.match)
+		}
+	}
+}
+
+func sentinels(n int) []error {
+	a := make([]error, n)
+	for i := range a {
+		a[i] = errors.New(strconv.Itoa(i))
+	}
+	return a
+}
+
+func BenchmarkSetMiss(b *testing.B) {
+	targets := sentinels(100)
+	set := errors.NewSet(targets...)
+	err := fmt.Errorf("a: %v", fmt.Errorf("b: %v", errors.New("c")))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		set.MayContain(err)
+	}
+}
+
+func BenchmarkLinearMiss(b *testing.B) {
+	targets := sentinels(100)
+	err := fmt.Errorf("a: %v", fmt.Errorf("b: %v", errors.New("c")))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, t := range targets {
+			if errors.Is(err, t) {
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import "sync"
+
+var sqlCodeExtractors struct {
+	sync.RWMutex
+	funcs []func(error) (int, bool)
+}
+
+// RegisterSQLCodeExtractor registers a function that returns the numeric code
+// of an error of a database driver, such as 1062 for a duplicate entry error
+// of MySQL, and reports whether err is such an error.
+//
+// Like RegisterUnwrapper, RegisterSQLCodeExtractor is typically called in init
+// functions, such as those of the packages that wrap a driver, but is safe for
+// concurrent use, including with calls to SQLCode.
+func RegisterSQLCodeExtractor(f func(err error) (code int, ok bool)) {
+	sqlCodeExtractors.Lock()
+	defer sqlCodeExtractors.Unlock()
+	sqlCodeExtractors.funcs = append(sqlCodeExtractors.funcs, f)
+}
+
+// SQLCode returns the code of the first error in err's chain, in the order of
+// Walk, for which a function registered with RegisterSQLCodeExtractor reports
+// a code. The functions are tried on each error in the order in which they
+// were registered. ok is false if none of them handles any error of the chain.
+func SQLCode(err error) (code int, ok bool) {
+	sqlCodeExtractors.RLock()
+	funcs := sqlCodeExtractors.funcs
+	sqlCodeExtractors.RUnlock()
+	if len(funcs) == 0 {
+		return 0, false
+	}
+	Walk(err, func(err error) bool {
+		for _, f := range funcs {
+			if c, handled := f(err); handled {
+				code, ok = c, true
+				return false
+			}
+		}
+		return true
+	})
+	return code, ok
+}
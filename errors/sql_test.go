@@ -0,0 +1,49 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors_test
+
+import (
+	"testing"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/fmt"
+)
+
+// driverError is an error of a database driver carrying a numeric code.
+type driverError struct {
+	Number  int
+	Message string
+}
+
+func (e *driverError) Error() string { return fmt.Sprintf("Error %d: %s", e.Number, e.Message) }
+
+func init() {
+	errors.RegisterSQLCodeExtractor(func(err error) (int, bool) {
+		if e, ok := err.(*driverError); ok {
+			return e.Number, true
+		}
+		return 0, false
+	})
+}
+
+func TestSQLCode(t *testing.T) {
+	dup := &driverError{1062, "Duplicate entry"}
+	testCases := []struct {
+		err  error
+		code int
+		ok   bool
+	}{
+		{nil, 0, false},
+		{errors.New("x"), 0, false},
+		{dup, 1062, true},
+		{fmt.Errorf("insert user: %v", dup), 1062, true},
+		{errors.Join(errors.New("x"), fmt.Errorf("insert: %v", dup)), 1062, true},
+	}
+	for _, tc := range testCases {
+		if code, ok := errors.SQLCode(tc.err); code != tc.code || ok != tc.ok {
+			t.Errorf("SQLCode(%v) = %d, %v; want %d, %v", tc.err, code, ok, tc.code, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// StackTrace returns the frames recorded by the errors in err's chain that
+// implement Framer, in the order in which Walk visits them: the frame of the
+// outermost error comes first. Errors with a zero frame are skipped, and the
+// errors hidden by Redact are not visited.
+func StackTrace(err error) []Frame {
+	var frames []Frame
+	Walk(err, func(err error) bool {
+		if f, ok := err.(Framer); ok {
+			if fr := f.Frame(); fr != (Frame{}) {
+				frames = append(frames, fr)
+			}
+		}
+		return !isRedacted(err)
+	})
+	return frames
+}
+
+// Root returns the innermost error of err's chain, found by following single
+// Unwrap links as for OTelAttributes, together with the innermost non-zero
+// frame recorded along the way, which is usually where the failure was first
+// reported. It walks the chain once. ok is false if there is no such frame;
+// leaf is nil only if err is nil.
+func Root(err error) (leaf error, frame Frame, ok bool) {
+	var w walker
+	for ; err != nil && !w.visited(err); err = Unwrap(err) {
+		leaf = err
+		if f, isFramer := err.(Framer); isFramer {
+			if fr := f.Frame(); fr != (Frame{}) {
+				frame, ok = fr, true
+			}
+		}
+	}
+	return leaf, frame, ok
+}
+
+// FrameInfo describes a frame in the form expected by error tracking
+// services, to which it is easily mapped.
+type FrameInfo struct {
+	Function string
+	File     string
+	Line     int
+
+	// InApp reports whether the frame belongs to the application rather than
+	// to a dependency, as decided by the predicate set with
+	// SetInAppPredicate.
+	InApp bool
+}
+
+var inApp func(function, file string) bool
+
+// SetInAppPredicate sets the function that decides the InApp field of the
+// frames returned by Frames from their function name and file. If f is nil,
+// the default, no frame is in the application. SetInAppPredicate should be
+// called early, for instance at the start of main, and not concurrently with
+// calls to Frames.
+func SetInAppPredicate(f func(function, file string) bool) {
+	inApp = f
+}
+
+// Frames returns the frames of StackTrace(err), in the same order, with
+// their location. Frames whose location is unknown are skipped.
+func Frames(err error) []FrameInfo {
+	var infos []FrameInfo
+	for _, fr := range StackTrace(err) {
+		function, file, line := fr.Location()
+		if file == "" {
+			continue
+		}
+		infos = append(infos, FrameInfo{
+			Function: function,
+			File:     file,
+			Line:     line,
+			InApp:    inApp != nil && inApp(function, file),
+		})
+	}
+	return infos
+}
+
+// SprintStack returns the frames of StackTrace(err) in the layout of
+// runtime.Stack, without the goroutine header: for each frame, the function
+// name on a line and the file and line, preceded by a tab, on the next. As
+// in a goroutine stack, the most recent call comes first, that is, the frame
+// of the innermost error. SprintStack returns "" if there are no frames.
+func SprintStack(err error) string {
+	return stackString(StackTrace(err))
+}
+
+// stackString renders frames in the layout of runtime.Stack, the most recent
+// call first: that is, starting from the frame of the innermost error.
+func stackString(frames []Frame) string {
+	var b strings.Builder
+	for i := len(frames) - 1; i >= 0; i-- {
+		function, file, line := frames[i].Location()
+		if file == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", function, file, line)
+	}
+	return b.String()
+}
+
+// OTelAttributes returns the values of the OpenTelemetry exception
+// attributes describing err: typ, for exception.type, is the dynamic type of
+// the innermost error of the chain, msg, for exception.message, is the text
+// of err, and stacktrace, for exception.stacktrace, lists the frames of
+// StackTrace as returned by SprintStack.
+//
+// The innermost error is found by following single Unwrap links; it is the
+// error wrapping several errors, if the chain reaches one, the error returned
+// by Redact, if the chain reaches one, or the last error reached before the
+// chain loops back to an error already reached.
+func OTelAttributes(err error) (typ, msg, stacktrace string) {
+	if err == nil {
+		return "", "", ""
+	}
+	leaf := err
+	var w walker
+	w.visited(leaf)
+	for next := Unwrap(leaf); next != nil && !isRedacted(leaf) && !w.visited(next); next = Unwrap(leaf) {
+		leaf = next
+	}
+	return reflect.TypeOf(leaf).String(), err.Error(), stackString(StackTrace(err))
+}
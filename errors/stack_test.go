@@ -0,0 +1,162 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/fmt"
+)
+
+func stackInner() error { return errors.New("inner") }
+
+func stackOuter() error { return fmt.Errorf("outer: %v", stackInner()) }
+
+func newStackInner(depth int) error { return errors.NewStack(1, depth, "deep") }
+
+func newStackOuter(depth int) error { return newStackMiddle(depth) }
+
+func newStackMiddle(depth int) error { return newStackInner(depth) }
+
+func TestNewStack(t *testing.T) {
+	err := newStackOuter(2)
+	if got, want := err.Error(), "deep"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	got := fmt.Sprintf("%+v", err)
+	re := regexp.MustCompile(`^deep:\n` +
+		`    golang.org/x/exp/errors_test.newStackMiddle\n        .*stack_test.go:\d+\n` +
+		`    golang.org/x/exp/errors_test.newStackOuter\n        .*stack_test.go:\d+\n    $`)
+	if !re.MatchString(got) {
+		t.Errorf("%%+v: got %q; want match for %s", got, re)
+	}
+	if function, _, _ := err.(errors.Framer).Frame().Location(); function != "golang.org/x/exp/errors_test.newStackMiddle" {
+		t.Errorf("Frame(): got %s; want newStackMiddle", function)
+	}
+	if n := strings.Count(fmt.Sprintf("%+v", newStackOuter(0)), "stack_test.go"); n != 1 {
+		t.Errorf("depth 0: %d frames printed, want 1", n)
+	}
+
+	err = errors.ErrorfStack(1, "code %d", 7)
+	got = fmt.Sprintf("%+v", err)
+	re = regexp.MustCompile(`^code 7:\n    golang.org/x/exp/errors_test.TestNewStack\n        .*stack_test.go:\d+\n    $`)
+	if !re.MatchString(got) {
+		t.Errorf("ErrorfStack %%+v: got %q; want match for %s", got, re)
+	}
+}
+
+func TestStackTrace(t *testing.T) {
+	if got := errors.StackTrace(errorT{}); got != nil {
+		t.Errorf("StackTrace(errorT{}) = %v, want nil", got)
+	}
+
+	frames := errors.StackTrace(&cyclic{"foreign", stackOuter()})
+	var got []string
+	for _, f := range frames {
+		function, _, _ := f.Location()
+		got = append(got, function)
+	}
+	want := []string{
+		"golang.org/x/exp/errors_test.stackOuter",
+		"golang.org/x/exp/errors_test.stackInner",
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("StackTrace: got %q; want %q", got, want)
+	}
+}
+
+func TestRoot(t *testing.T) {
+	if leaf, frame, ok := errors.Root(nil); leaf != nil || frame != (errors.Frame{}) || ok {
+		t.Errorf("Root(nil) = %v, %v, %v; want nil, zero frame, false", leaf, frame, ok)
+	}
+	if leaf, _, ok := errors.Root(errorT{}); leaf != (errorT{}) || ok {
+		t.Errorf("Root(errorT{}) = %v, _, %v; want errorT{}, false", leaf, ok)
+	}
+
+	testCases := []struct {
+		err      error
+		leaf     string
+		function string
+	}{
+		{fmt.Errorf("wrap: %v", stackOuter()), "inner", "golang.org/x/exp/errors_test.stackInner"},
+		{fmt.Errorf("wrap: %v", errorT{}), "errorT", "golang.org/x/exp/errors_test.TestRoot"},
+	}
+	for _, tc := range testCases {
+		leaf, frame, ok := errors.Root(tc.err)
+		function, _, _ := frame.Location()
+		if leaf.Error() != tc.leaf || function != tc.function || !ok {
+			t.Errorf("Root(%v) = %v, %s, %v; want %s, %s, true", tc.err, leaf, function, ok, tc.leaf, tc.function)
+		}
+	}
+}
+
+func TestFrames(t *testing.T) {
+	defer errors.SetInAppPredicate(nil)
+
+	if got := errors.Frames(errorT{}); got != nil {
+		t.Errorf("Frames(errorT{}) = %v, want nil", got)
+	}
+	frames := errors.Frames(stackOuter())
+	if len(frames) != 2 {
+		t.Fatalf("Frames: got %d frames; want 2", len(frames))
+	}
+	if got, want := frames[0].Function, "golang.org/x/exp/errors_test.stackOuter"; got != want {
+		t.Errorf("Function: got %q; want %q", got, want)
+	}
+	if !strings.HasSuffix(frames[0].File, "stack_test.go") || frames[0].Line == 0 || frames[0].InApp {
+		t.Errorf("first frame: got %+v; want stack_test.go, non-zero line, not in app", frames[0])
+	}
+
+	errors.SetInAppPredicate(func(function, file string) bool {
+		return strings.HasSuffix(function, ".stackInner")
+	})
+	frames = errors.Frames(stackOuter())
+	if frames[0].InApp || !frames[1].InApp {
+		t.Errorf("InApp: got %v, %v; want false, true", frames[0].InApp, frames[1].InApp)
+	}
+}
+
+func TestOTelAttributes(t *testing.T) {
+	typ, msg, stack := errors.OTelAttributes(stackOuter())
+	if want := "*errors.errorString"; typ != want {
+		t.Errorf("type: got %q; want %q", typ, want)
+	}
+	if want := "outer: inner"; msg != want {
+		t.Errorf("message: got %q; want %q", msg, want)
+	}
+	re := regexp.MustCompile(`^golang.org/x/exp/errors_test.stackInner\n\t.*stack_test.go:\d+\n` +
+		`golang.org/x/exp/errors_test.stackOuter\n\t.*stack_test.go:\d+\n$`)
+	if !re.MatchString(stack) {
+		t.Errorf("stacktrace: got %q; want match for %s", stack, re)
+	}
+
+	if typ, msg, stack := errors.OTelAttributes(nil); typ != "" || msg != "" || stack != "" {
+		t.Errorf("OTelAttributes(nil) = %q, %q, %q; want empty", typ, msg, stack)
+	}
+
+	loop := &cyclic{msg: "loop"}
+	loop.next = fmt.Errorf("wrap: %v", loop)
+	if typ, _, _ := errors.OTelAttributes(loop); typ != "*fmt.withChain" {
+		t.Errorf("OTelAttributes(cycle): type %q; want %q", typ, "*fmt.withChain")
+	}
+}
+
+func TestSprintStack(t *testing.T) {
+	got := errors.SprintStack(fmt.Errorf("wrap: %v", stackOuter()))
+	re := regexp.MustCompile(`^golang.org/x/exp/errors_test.stackInner\n\t.*stack_test.go:\d+\n` +
+		`golang.org/x/exp/errors_test.stackOuter\n\t.*stack_test.go:\d+\n` +
+		`golang.org/x/exp/errors_test.TestSprintStack\n\t.*stack_test.go:\d+\n$`)
+	if !re.MatchString(got) {
+		t.Errorf("SprintStack: got %q; want match for %s", got, re)
+	}
+	for _, err := range []error{nil, errorT{}} {
+		if got := errors.SprintStack(err); got != "" {
+			t.Errorf("SprintStack(%v) = %q, want \"\"", err, got)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import "encoding/json"
+
+// defaultStatus is the status reported for errors that do not declare one.
+const defaultStatus = 500 // Internal Server Error
+
+// WithStatus returns an error that annotates err with an HTTP status code.
+// The returned error formats as err and unwraps to err.
+// WithStatus returns nil if err is nil.
+func WithStatus(err error, status int) error {
+	if err == nil {
+		return nil
+	}
+	return &withStatus{err, status}
+}
+
+type withStatus struct {
+	err    error
+	status int
+}
+
+func (e *withStatus) Error() string { return e.err.Error() }
+
+func (e *withStatus) Format(p Printer) (next error) {
+	return formatInPlace(p, e.err)
+}
+
+func (e *withStatus) Unwrap() error { return e.err }
+
+func (e *withStatus) CloneWrapping(errs []error) error {
+	c := *e
+	c.err = errs[0]
+	return &c
+}
+
+// Status returns the outermost HTTP status code attached to err's chain with
+// WithStatus. It returns 500 if err is non-nil and no status was attached,
+// and 0 if err is nil.
+func Status(err error) int {
+	if err == nil {
+		return 0
+	}
+	var s *withStatus
+	if As(err, &s) {
+		return s.status
+	}
+	return defaultStatus
+}
+
+// ProblemJSON returns an RFC 7807 problem document describing err.
+// The title is the message of the outermost error, the detail is the
+// message of the whole chain, and the status is the result of Status.
+// ProblemJSON returns nil if err is nil.
+func ProblemJSON(err error) ([]byte, error) {
+	if err == nil {
+		return nil, nil
+	}
+	return json.Marshal(problem{
+		Status: Status(err),
+		Title:  message(err),
+		Detail: Format(err, false),
+	})
+}
+
+type problem struct {
+	Status int    `json:"status"`
+	Title  string `json:"title"`
+	Detail string `json:"detail"`
+}
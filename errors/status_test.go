@@ -0,0 +1,60 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors_test
+
+import (
+	"testing"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/fmt"
+)
+
+func TestStatus(t *testing.T) {
+	err1 := errors.New("1")
+	testCases := []struct {
+		err  error
+		want int
+	}{
+		{nil, 0},
+		{err1, 500},
+		{errors.WithStatus(err1, 404), 404},
+		{fmt.Errorf("wrap: %v", errors.WithStatus(err1, 404)), 404},
+		{errors.WithStatus(errors.WithStatus(err1, 404), 409), 409},
+	}
+	for _, tc := range testCases {
+		if got := errors.Status(tc.err); got != tc.want {
+			t.Errorf("Status(%v) = %v, want %v", tc.err, got, tc.want)
+		}
+	}
+	if err := errors.WithStatus(nil, 404); err != nil {
+		t.Errorf("WithStatus(nil, 404) = %v, want nil", err)
+	}
+}
+
+func TestWithStatusTransparent(t *testing.T) {
+	err1 := errors.New("1")
+	err := errors.WithStatus(fmt.Errorf("wrap: %v", err1), 404)
+	if got, want := err.Error(), "wrap: 1"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if got, want := fmt.Sprint(err), "wrap: 1"; got != want {
+		t.Errorf("Sprint = %q, want %q", got, want)
+	}
+	if !errors.Is(err, err1) {
+		t.Errorf("Is(%v, %v) = false, want true", err, err1)
+	}
+}
+
+func TestProblemJSON(t *testing.T) {
+	err := errors.WithStatus(fmt.Errorf("user not found: %v", errors.New("no rows")), 404)
+	got, jerr := errors.ProblemJSON(err)
+	if jerr != nil {
+		t.Fatal(jerr)
+	}
+	want := `{"status":404,"title":"user not found","detail":"user not found: no rows"}`
+	if string(got) != want {
+		t.Errorf("ProblemJSON:\n got: %s\nwant: %s", got, want)
+	}
+}
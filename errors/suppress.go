@@ -0,0 +1,69 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+// Suppress returns an error that annotates err with errors that were
+// deliberately ignored, such as failures of cleanup operations after err,
+// for the record. The returned error formats as err, followed by the
+// suppressed errors when printed with detail, and unwraps to err. The
+// suppressed errors are not part of its chain: Is, As and Walk do not reach
+// them, and they are only returned by Suppressed.
+//
+// Nil suppressed errors are discarded. Suppress returns err if all suppressed
+// errors are nil, and nil if err is nil.
+func Suppress(err error, suppressed ...error) error {
+	if err == nil {
+		return nil
+	}
+	var a []error
+	for _, s := range suppressed {
+		if s != nil {
+			a = append(a, s)
+		}
+	}
+	if len(a) == 0 {
+		return err
+	}
+	return &withSuppressed{err, a}
+}
+
+type withSuppressed struct {
+	err        error
+	suppressed []error
+}
+
+func (e *withSuppressed) Error() string { return e.err.Error() }
+
+func (e *withSuppressed) Format(p Printer) (next error) {
+	next = formatInPlace(p, e.err)
+	if p.Detail() {
+		for _, s := range e.suppressed {
+			p.Printf("suppressed: %s\n", Format(s, true))
+		}
+	}
+	return next
+}
+
+func (e *withSuppressed) Unwrap() error { return e.err }
+
+func (e *withSuppressed) CloneWrapping(errs []error) error {
+	c := *e
+	c.err = errs[0]
+	return &c
+}
+
+// Suppressed returns the errors attached to err's chain with Suppress, in the
+// order of Walk and, for each call to Suppress, in the order of its
+// arguments.
+func Suppressed(err error) []error {
+	var errs []error
+	Walk(err, func(err error) bool {
+		if s, ok := err.(*withSuppressed); ok {
+			errs = append(errs, s.suppressed...)
+		}
+		return true
+	})
+	return errs
+}
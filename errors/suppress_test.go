@@ -0,0 +1,50 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors_test
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/fmt"
+)
+
+func TestSuppress(t *testing.T) {
+	errWrite := errors.New("write failed")
+	errClose := errors.New("close failed")
+	errUnlock := errors.New("unlock failed")
+
+	if err := errors.Suppress(nil, errClose); err != nil {
+		t.Errorf("Suppress(nil, errClose) = %v, want nil", err)
+	}
+	if err := errors.Suppress(errWrite, nil); err != errWrite {
+		t.Errorf("Suppress(errWrite, nil) = %v, want errWrite", err)
+	}
+
+	err := fmt.Errorf("save: %v", errors.Suppress(errWrite, errClose, nil, errUnlock))
+	if got, want := err.Error(), "save: write failed"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if !errors.Is(err, errWrite) {
+		t.Errorf("Is(err, errWrite) = false, want true")
+	}
+	if errors.Is(err, errClose) {
+		t.Errorf("Is(err, errClose) = true, want false")
+	}
+	if got, want := errors.Suppressed(err), []error{errClose, errUnlock}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Suppressed(err) = %v, want %v", got, want)
+	}
+	if got := errors.Suppressed(errWrite); got != nil {
+		t.Errorf("Suppressed(errWrite) = %v, want nil", got)
+	}
+}
+
+func TestSuppressFormat(t *testing.T) {
+	err := errors.Suppress(errorT{}, errorD{})
+	if got, want := fmt.Sprintf("%+v", err), "errorT:\n    suppressed: errorD:\n        detail\n    "; got != want {
+		t.Errorf("%%+v: got %q; want %q", got, want)
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+// Walk traverses the tree of errors rooted at err in pre-order: it calls
+// visit for err and, if visit returns true, walks the errors it wraps.
+//
+// An error wraps other errors if it implements Wrapper or has a method
+//
+//	Unwrap() []error
+//
+// in which case the wrapped errors are walked in order. Errors implementing
+// neither are unwrapped with the functions registered with RegisterUnwrapper,
+// if any. Nil errors are not visited. An error that is reachable more than
+// once, for instance through a cycle, is only visited the first time it is
+// reached. Only errors whose dynamic type is a pointer are tracked for this
+// purpose, as other values cannot form cycles.
+func Walk(err error, visit func(err error) (descend bool)) {
+	var w walker
+	w.walk(err, visit)
+}
+
+// A Walker iterates over the tree of errors rooted at an error, visiting
+// the same errors in the same order as Walk, for code that inspects many
+// errors on hot paths: a Walker can be reset to walk another error, reusing
+// the memory it allocated to keep track of the visited errors and of the
+// errors to visit. The zero value is a Walker with no errors to visit.
+//
+// A Walker is not safe for concurrent use, and must not be copied after its
+// first use, but it can be reused sequentially.
+type Walker struct {
+	w walker
+	// stack holds the errors to visit, the next one last.
+	stack []error
+}
+
+// Reset makes w walk the tree of errors rooted at err, forgetting about the
+// errors it visited before.
+func (w *Walker) Reset(err error) {
+	w.w.reset()
+	for i := range w.stack {
+		w.stack[i] = nil
+	}
+	w.stack = w.stack[:0]
+	if err != nil {
+		w.stack = append(w.stack, err)
+	}
+}
+
+// Next returns the next error of the tree, and reports false, with a nil
+// error, once all of its errors have been visited.
+func (w *Walker) Next() (error, bool) {
+	for len(w.stack) > 0 {
+		err := w.stack[len(w.stack)-1]
+		w.stack[len(w.stack)-1] = nil
+		w.stack = w.stack[:len(w.stack)-1]
+		if w.w.visited(err) {
+			continue
+		}
+		var next error
+		switch u := err.(type) {
+		case interface{ Unwrap() []error }:
+			errs := u.Unwrap()
+			for i := len(errs) - 1; i >= 0; i-- {
+				if errs[i] != nil {
+					w.stack = append(w.stack, errs[i])
+				}
+			}
+		case Wrapper:
+			next = u.Unwrap()
+		case noWrapper:
+		default:
+			next = unwrapRegistered(err)
+		}
+		if next != nil {
+			w.stack = append(w.stack, next)
+		}
+		return err, true
+	}
+	return nil, false
+}
+
+// walkWithin returns a function like Walk that visits at most n errors.
+func walkWithin(n int) func(error, func(error) bool) {
+	return limitWalk(Walk, n)
+}
+
+// limitWalk returns a function like walk that visits at most n errors.
+func limitWalk(walk func(error, func(error) bool), n int) func(error, func(error) bool) {
+	return func(err error, visit func(error) bool) {
+		walk(err, func(err error) bool {
+			if n <= 0 {
+				return false
+			}
+			n--
+			return visit(err)
+		})
+	}
+}
+
+// walkInternal is like Walk but also walks the errors hidden by Opaque.
+func walkInternal(err error, visit func(error) bool) {
+	w := walker{opaque: true}
+	w.walk(err, visit)
+}
+
+// walkDeep is like walkInternal but also walks the errors attached with
+// Suppress, after the error they are attached to.
+func walkDeep(err error, visit func(error) bool) {
+	w := walker{opaque: true}
+	var deep func(error) bool
+	deep = func(err error) bool {
+		if !visit(err) {
+			return false
+		}
+		if s, ok := err.(*withSuppressed); ok {
+			for _, err := range s.suppressed {
+				w.walk(err, deep)
+			}
+		}
+		return true
+	}
+	w.walk(err, deep)
+}
+
+// A walker keeps track of the errors visited during a walk.
+type walker struct {
+	// opaque reports whether to walk the errors hidden by Opaque.
+	opaque bool
+
+	seen  []uintptr
+	small [8]uintptr
+	big   map[uintptr]bool
+}
+
+// maxSeen is the number of visited errors above which the walker switches
+// from a linear scan to a map.
+const maxSeen = 32
+
+func (w *walker) walk(err error, visit func(error) bool) {
+	for err != nil {
+		if w.visited(err) || !visit(err) {
+			return
+		}
+		switch u := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, err := range u.Unwrap() {
+				w.walk(err, visit)
+			}
+			return
+		case Wrapper:
+			err = u.Unwrap()
+		case noWrapper:
+			if !w.opaque {
+				return
+			}
+			err = u.error
+		default:
+			err = unwrapRegistered(err)
+		}
+	}
+}
+
+// isPath implements IsPath: it returns the path to the first error matching
+// target in the walk of err, appended to path.
+func (w *walker) isPath(err, target error, cmp bool, path []int) ([]int, bool) {
+	for err != nil {
+		if w.visited(err) {
+			return nil, false
+		}
+		if matches(err, target, cmp) {
+			return path, true
+		}
+		switch u := err.(type) {
+		case interface{ Unwrap() []error }:
+			for i, err := range u.Unwrap() {
+				if p, ok := w.isPath(err, target, cmp, append(path, i)); ok {
+					return p, true
+				}
+			}
+			return nil, false
+		case Wrapper:
+			err = u.Unwrap()
+		case noWrapper:
+			if !w.opaque {
+				return nil, false
+			}
+			err = u.error
+		default:
+			err = unwrapRegistered(err)
+		}
+	}
+	return nil, false
+}
+
+// reset forgets the visited errors, keeping the memory used to track them.
+func (w *walker) reset() {
+	w.seen = w.seen[:0]
+	for p := range w.big {
+		delete(w.big, p)
+	}
+}
+
+// visited reports whether err was visited before and marks it as visited.
+func (w *walker) visited(err error) bool {
+	p, ok := pointer(err)
+	if !ok {
+		return false
+	}
+	if w.big != nil {
+		if w.big[p] {
+			return true
+		}
+		w.big[p] = true
+		return false
+	}
+	for _, q := range w.seen {
+		if q == p {
+			return true
+		}
+	}
+	if len(w.seen) == maxSeen {
+		w.big = make(map[uintptr]bool, 2*maxSeen)
+		for _, q := range w.seen {
+			w.big[q] = true
+		}
+		w.big[p] = true
+		return false
+	}
+	if w.seen == nil {
+		w.seen = w.small[:0]
+	}
+	w.seen = append(w.seen, p)
+	return false
+}
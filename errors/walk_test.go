@@ -0,0 +1,158 @@
+// Copyright 2018 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package errors_test
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/errors"
+	"golang.org/x/exp/errors/fmt"
+)
+
+// multi is an error wrapping several errors.
+type multi []error
+
+func (multi) Error() string { return "multi" }
+
+func (m multi) Unwrap() []error { return m }
+
+// cyclic is an error that may wrap itself.
+type cyclic struct {
+	msg  string
+	next error
+}
+
+func (e *cyclic) Error() string { return e.msg }
+
+func (e *cyclic) Unwrap() error { return e.next }
+
+func TestWalk(t *testing.T) {
+	err1 := errors.New("1")
+	err2 := errors.New("2")
+	err3 := errors.New("3")
+	wrap1 := fmt.Errorf("wrap 1: %v", err1)
+	loop := &cyclic{msg: "loop"}
+	loop.next = &cyclic{"loop 2", loop}
+
+	testCases := []struct {
+		err   error
+		prune error
+		want  []error
+	}{
+		{nil, nil, nil},
+		{err1, nil, []error{err1}},
+		{wrap1, nil, []error{wrap1, err1}},
+		{wrap1, wrap1, []error{wrap1}},
+		{multi{wrap1, nil, err2}, nil, []error{multi{wrap1, nil, err2}, wrap1, err1, err2}},
+		{multi{wrap1, err3}, wrap1, []error{multi{wrap1, err3}, wrap1, err3}},
+		{multi{err1, err1}, nil, []error{multi{err1, err1}, err1}},
+		{loop, nil, []error{loop, loop.next}},
+	}
+	for _, tc := range testCases {
+		var got []error
+		errors.Walk(tc.err, func(err error) bool {
+			got = append(got, err)
+			return err != tc.prune
+		})
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Walk(%v): visited %v, want %v", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestWalker(t *testing.T) {
+	err1 := errors.New("1")
+	wrap1 := fmt.Errorf("wrap 1: %v", err1)
+	loop := &cyclic{msg: "loop"}
+	loop.next = &cyclic{"loop 2", loop}
+	var long error = errors.New("leaf")
+	for i := 0; i < 100; i++ {
+		long = &cyclic{"link", long}
+	}
+
+	var w errors.Walker
+	if err, ok := w.Next(); err != nil || ok {
+		t.Errorf("zero Walker: Next() = %v, %v; want nil, false", err, ok)
+	}
+	for _, err := range []error{
+		nil,
+		wrap1,
+		multi{wrap1, nil, errors.New("2")},
+		multi{err1, multi{err1, wrap1}},
+		loop,
+		errors.Opaque(wrap1),
+		long,
+		// Visited again after a reset.
+		wrap1,
+	} {
+		var want, got []error
+		errors.Walk(err, func(err error) bool {
+			want = append(want, err)
+			return true
+		})
+		w.Reset(err)
+		for err, ok := w.Next(); ok; err, ok = w.Next() {
+			got = append(got, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Walker(%v): visited %v, want %v", err, got, want)
+		}
+	}
+
+	allocs := testing.AllocsPerRun(10, func() {
+		w.Reset(long)
+		for _, ok := w.Next(); ok; _, ok = w.Next() {
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("reused Walker: %v allocations, want 0", allocs)
+	}
+}
+
+func TestWalkLongChain(t *testing.T) {
+	var err error = errors.New("leaf")
+	for i := 0; i < 100; i++ {
+		err = &cyclic{"link", err}
+	}
+	n := 0
+	errors.Walk(err, func(error) bool {
+		n++
+		return true
+	})
+	if n != 101 {
+		t.Errorf("Walk visited %d errors, want 101", n)
+	}
+}
+
+func TestIsMulti(t *testing.T) {
+	err1 := errors.New("1")
+	err2 := errors.New("2")
+	err := fmt.Errorf("wrap: %v", multi{fmt.Errorf("a: %v", err1), err2})
+	for _, target := range []error{err1, err2} {
+		if !errors.Is(err, target) {
+			t.Errorf("Is(%v, %v) = false, want true", err, target)
+		}
+	}
+	var errT errorT
+	if !errors.As(multi{err1, errorT{}}, &errT) {
+		t.Errorf("As(multi, &errT) = false, want true")
+	}
+}
+
+func BenchmarkWalker(b *testing.B) {
+	var err error = errors.New("leaf")
+	for i := 0; i < 20; i++ {
+		err = &cyclic{"link", err}
+	}
+	var w errors.Walker
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w.Reset(err)
+		for _, ok := w.Next(); ok; _, ok = w.Next() {
+		}
+	}
+}
@@ -6,6 +6,9 @@ package errors
 
 import (
 	"reflect"
+	"sync"
+
+	"golang.org/x/exp/errors/internal"
 )
 
 // An Wrapper provides context around another error.
@@ -17,6 +20,7 @@ type Wrapper interface {
 
 // Opaque returns an error with the same error formatting as err
 // but that does not match err and cannot be unwrapped.
+// Only AsInternal and DeepAs see through it.
 func Opaque(err error) error {
 	return noWrapper{err}
 }
@@ -26,43 +30,420 @@ type noWrapper struct {
 }
 
 func (e noWrapper) Format(p Printer) (next error) {
-	if f, ok := e.error.(Formatter); ok {
-		return f.Format(p)
-	}
-	p.Print(e.error)
-	return nil
+	return formatInPlace(p, e.error)
 }
 
 // Unwrap returns the next error in err's chain.
 // If there is no next error, Unwrap returns nil.
+//
+// If err implements neither Wrapper nor Unwrap() []error, Unwrap returns the
+// result of the first function registered with RegisterUnwrapper that
+// unwraps it.
 func Unwrap(err error) error {
-	u, ok := err.(Wrapper)
-	if !ok {
+	switch u := err.(type) {
+	case Wrapper:
+		return u.Unwrap()
+	case interface{ Unwrap() []error }:
+		return nil
+	}
+	return unwrapRegistered(err)
+}
+
+var unwrappers struct {
+	sync.RWMutex
+	funcs []func(error) (error, bool)
+}
+
+// RegisterUnwrapper registers a function that unwraps errors not
+// implementing the Unwrap convention, such as errors of other packages that
+// expose their cause with a method
+//
+//	Cause() error
+//
+// The function reports whether it handles err and, if so, returns the next
+// error in err's chain, which may be nil.
+//
+// Unwrap, Walk and the functions built on them consult the registered
+// functions, in the order in which they were registered, for any error that
+// has neither an Unwrap() error nor an Unwrap() []error method; the first
+// one that handles the error wins. They are not consulted for the errors of
+// this package and of package errors/fmt, which never need them.
+// Registration is typically done in init functions, but RegisterUnwrapper
+// is safe for concurrent use, including with traversals. The functions
+// themselves may also be called concurrently.
+func RegisterUnwrapper(f func(err error) (next error, ok bool)) {
+	unwrappers.Lock()
+	defer unwrappers.Unlock()
+	unwrappers.funcs = append(unwrappers.funcs, f)
+}
+
+// unwrapRegistered unwraps err with the functions registered with
+// RegisterUnwrapper.
+func unwrapRegistered(err error) error {
+	if _, ok := err.(internal.PlainMatcher); ok {
 		return nil
 	}
-	return u.Unwrap()
+	unwrappers.RLock()
+	funcs := unwrappers.funcs
+	unwrappers.RUnlock()
+	for _, f := range funcs {
+		if next, ok := f(err); ok {
+			return next
+		}
+	}
+	return nil
 }
 
-// Is returns true if any error in err's chain is equal to target.
+// Is returns true if any error in err's chain matches target.
+//
+// The chain consists of err and the errors reached from it by Walk; only the
+// chain of err is walked, not that of target. So a sentinel error defined by
+// wrapping a more general one, as in
+//
+//	var ErrUserNotFound = fmt.Errorf("user: %w", ErrNotFound)
+//
+// matches ErrNotFound, but ErrNotFound does not match it. An error
+// matches target if it is equal to target or if it has a method
+//
+//	Is(error) bool
+//
+// such that Is(target) returns true. If the type of target is not comparable,
+// as for a struct with a slice field, errors are not compared with it and
+// only match it through their Is method. If target implements Target, an error
+// matches it instead if target.MatchError reports true for it.
+//
+// A nil target matches only a nil err: the errors of a non-nil chain are
+// neither compared with it nor passed to their Is method.
+//
+// If a hook is set with SetOnMatch, it is called with the result.
 func Is(err, target error) bool {
-	if target == nil {
-		return err == target
+	ok := isChain(err, target)
+	if onMatch != nil {
+		onMatch(err, target, ok)
+	}
+	return ok
+}
+
+// isChain implements Is without calling the hook set with SetOnMatch, for
+// the functions of this package that test errors with Is.
+func isChain(err, target error) bool {
+	if _, ok := target.(Target); !ok && target != nil && plainMatch(err) {
+		return isEqual(err, target)
 	}
-	for {
+	return is(err, target, Walk)
+}
+
+var onMatch func(err, target error, matched bool)
+
+// SetOnMatch sets a function called by Is with its arguments and result,
+// once per call, for instance to count how often each sentinel error is
+// matched. It is not called for the comparisons made by other functions of
+// this package. A nil f, the default, removes the hook. SetOnMatch should be
+// called early, for instance at the start of main, and not concurrently with
+// calls to Is.
+func SetOnMatch(f func(err, target error, matched bool)) {
+	onMatch = f
+}
+
+// plainMatch reports whether the errors of err's chain can only match a
+// target by being equal to it. It is the case for chains made of errors of
+// this package and of package errors/fmt that have no Is method of their
+// own, unless matching by value is enabled. Such chains cannot form cycles,
+// and registered unwrappers do not apply to them.
+func plainMatch(err error) bool {
+	p, ok := err.(internal.PlainMatcher)
+	return ok && p.PlainMatch() && !internal.SentinelMatchByValue
+}
+
+// isEqual reports whether an error of err's chain, for which plainMatch
+// holds, is equal to target.
+func isEqual(err, target error) bool {
+	for err != nil {
 		if err == target {
 			return true
 		}
-		if err = Unwrap(err); err == nil {
+		switch u := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, err := range u.Unwrap() {
+				if isEqual(err, target) {
+					return true
+				}
+			}
+			return false
+		case Wrapper:
+			err = u.Unwrap()
+		default:
 			return false
 		}
 	}
+	return false
+}
+
+// IsWithin is like Is but only examines the first maxDepth errors of err's
+// chain, in the order of Walk. It reports false if target is not found
+// within that bound, which limits the cost of inspecting errors of untrusted
+// origin.
+func IsWithin(err, target error, maxDepth int) bool {
+	return is(err, target, walkWithin(maxDepth))
+}
+
+// IsNode is like Is but also returns the first error in err's chain that
+// matches target, so that its fields can be inspected. If target is nil,
+// IsNode reports whether err is nil and returns a nil node.
+func IsNode(err, target error) (node error, ok bool) {
+	return isNode(err, target, Walk)
+}
+
+// IsPath is like Is, but also returns the location of the first error in
+// err's chain matching target, as the indices of the branches taken at each
+// error wrapping several errors on the way from err to the matching error.
+// The path is empty, but not nil, if no such error is crossed, as for a chain
+// of errors wrapping a single error. IsPath returns nil and false if no error
+// matches target.
+func IsPath(err, target error) (path []int, ok bool) {
+	if target == nil {
+		if err == nil {
+			return []int{}, true
+		}
+		return nil, false
+	}
+	var w walker
+	return w.isPath(err, target, isComparable(target), []int{})
+}
+
+// UnwrapTo returns the first error of err's chain, following Unwrap from err,
+// for which pred returns true, together with the rest of the chain it wraps.
+// It is like IsNode with a predicate rather than a target. UnwrapTo returns
+// nil and false if pred returns false for all these errors.
+//
+// Unlike Walk, UnwrapTo does not descend into the errors wrapped by an error
+// that wraps several errors: it stops at such an error, after calling pred
+// for it, and returns false, as the errors beyond it are not a single chain.
+func UnwrapTo(err error, pred func(error) bool) (node error, ok bool) {
+	var w walker
+	for ; err != nil && !w.visited(err); err = Unwrap(err) {
+		if pred(err) {
+			return err, true
+		}
+		if _, ok := err.(interface{ Unwrap() []error }); ok {
+			break
+		}
+	}
+	return nil, false
+}
+
+func is(err, target error, walk func(error, func(error) bool)) bool {
+	_, ok := isNode(err, target, walk)
+	return ok
+}
+
+func isNode(err, target error, walk func(error, func(error) bool)) (node error, ok bool) {
+	if target == nil {
+		return nil, err == target
+	}
+	cmp := isComparable(target)
+	walk(err, func(err error) bool {
+		if !ok && matches(err, target, cmp) {
+			node, ok = err, true
+		}
+		return !ok
+	})
+	return node, ok
+}
+
+// A Target is a target of Is that decides itself which errors match it,
+// such as a matcher of all the errors carrying a given code.
+type Target interface {
+	error
+
+	// MatchError reports whether err, one of the errors of the chain passed
+	// to Is, matches the target.
+	MatchError(err error) bool
+}
+
+// Any is a target of Is that matches any error, so that Is(err, Any) reports
+// whether err is non-nil, as in tests asserting that some error occurred.
+var Any error = anyTarget{}
+
+type anyTarget struct{}
+
+func (anyTarget) Error() string { return "any error" }
+
+func (anyTarget) MatchError(err error) bool { return true }
+
+// matches reports whether target, if it implements Target, accepts err,
+// or else whether err is target or has a method Is(error) bool reporting
+// that it matches target. Comparing err and target with == would panic if
+// they had the same type and it was not comparable, so they are only
+// compared if cmp, the result of isComparable for target, is set.
+func matches(err, target error, cmp bool) bool {
+	if t, ok := target.(Target); ok {
+		return t.MatchError(err)
+	}
+	if cmp && err == target {
+		return true
+	}
+	x, ok := err.(interface{ Is(error) bool })
+	return ok && x.Is(target)
+}
+
+// isComparable reports whether errors can be compared with target with ==.
+func isComparable(target error) bool {
+	return reflect.TypeOf(target).Comparable()
+}
+
+// IsAll is like Is, except that when err's chain reaches an error that wraps
+// several errors, it reports whether each of them matches target with Is,
+// rather than any. It supports decisions that must hold for every failure of
+// a joined error, such as retrying only if all of them are temporary.
+//
+// The chain is followed as by Unwrap until an error matches target, in which
+// case IsAll returns true, or an error wrapping several errors is reached.
+// An error wrapping no errors at all matches nothing: IsAll reports false
+// for it, as there is no failure to establish the condition for.
+func IsAll(err, target error) bool {
+	if target == nil {
+		return err == target
+	}
+	cmp := isComparable(target)
+	var w walker
+	for err != nil && !w.visited(err) {
+		if matches(err, target, cmp) {
+			return true
+		}
+		if u, ok := err.(interface{ Unwrap() []error }); ok {
+			errs := u.Unwrap()
+			for _, err := range errs {
+				if !isChain(err, target) {
+					return false
+				}
+			}
+			return len(errs) > 0
+		}
+		err = Unwrap(err)
+	}
+	return false
+}
+
+// IsOneOf reports whether any error in err's chain matches any of the
+// targets, as defined by Is. It is equivalent to calling Is for each target,
+// but walks the chain only once: each error of the chain, in the order of
+// Walk, is compared against each target in turn, and IsOneOf returns on the
+// first match.
+//
+// As with Is, a nil target only matches a nil err.
+func IsOneOf(err error, targets ...error) bool {
+	if err == nil {
+		for _, t := range targets {
+			if t == nil {
+				return true
+			}
+		}
+		return false
+	}
+	cmp := make([]bool, len(targets))
+	for i, t := range targets {
+		cmp[i] = t != nil && isComparable(t)
+	}
+	found := false
+	Walk(err, func(err error) bool {
+		for i, t := range targets {
+			if found {
+				break
+			}
+			found = t != nil && matches(err, t, cmp[i])
+		}
+		return !found
+	})
+	return found
+}
+
+// A Case is a case of Switch: Do is called if err matches Target.
+type Case struct {
+	Target error
+	Do     func()
+}
+
+// Switch calls the Do function of the first of cases whose Target matches
+// err, as defined by Is, and reports whether one was called. Cases take
+// precedence in the order given, regardless of the position in err's chain
+// of the errors that match them. Unlike a switch statement calling Is for
+// each case, Switch walks the chain only once.
+func Switch(err error, cases ...Case) bool {
+	matched := make([]bool, len(cases))
+	if err == nil {
+		for i, c := range cases {
+			matched[i] = c.Target == nil
+		}
+	} else {
+		cmp := make([]bool, len(cases))
+		for i, c := range cases {
+			cmp[i] = c.Target != nil && isComparable(c.Target)
+		}
+		Walk(err, func(err error) bool {
+			for i, c := range cases {
+				if !matched[i] && c.Target != nil {
+					matched[i] = matches(err, c.Target, cmp[i])
+				}
+			}
+			return true
+		})
+	}
+	for i, c := range cases {
+		if matched[i] {
+			c.Do()
+			return true
+		}
+	}
+	return false
 }
 
 // As finds the first error in err's chain that matches a type to which target
 // points, and if so, sets the target to its value and reports success.
+// The chain consists of err and the errors reached from it by Walk. An error
+// also matches if it has a method
+//
+//	As(interface{}) bool
+//
+// such that As(target) returns true, in which case that method sets target.
+//
+// If target is instead a pointer to a slice of a type T, and the slice type
+// is not itself an error, As appends every error in err's chain of type T to
+// the slice and reports whether there was at least one.
 //
 // As will panic if target is nil.
 func As(err error, target interface{}) bool {
+	return as(err, target, Walk)
+}
+
+// AsWithin is like As but only examines the first maxDepth errors of err's
+// chain, in the order of Walk.
+func AsWithin(err error, target interface{}, maxDepth int) bool {
+	return as(err, target, walkWithin(maxDepth))
+}
+
+// AsInternal is like As but also examines the errors hidden by Opaque. It
+// is meant for trusted diagnostics, such as logging, of errors whose causes
+// are deliberately hidden from the code handling them.
+func AsInternal(err error, target interface{}) bool {
+	return as(err, target, walkInternal)
+}
+
+// DeepAs is like As for a target of type *T, but returns the error it finds
+// and examines every error reachable from err: the errors of its chain, the
+// errors hidden by Opaque and the errors attached with Suppress, as well as
+// their own chains. It deliberately ignores the intent of Opaque and Suppress
+// to keep errors from the code handling err, and is only meant for trusted
+// diagnostics tools, such as crash reporters, never for deciding how to
+// handle an error. Each error is examined only once, even if it is reachable
+// several times, as through a cycle.
+func DeepAs[T error](err error) (T, bool) {
+	var target T
+	ok := as(err, &target, walkDeep)
+	return target, ok
+}
+
+func as(err error, target interface{}, walk func(error, func(error) bool)) bool {
 	if target == nil {
 		panic("errors: target cannot be nil")
 	}
@@ -71,13 +452,59 @@ func As(err error, target interface{}) bool {
 		panic("errors: target must be a pointer")
 	}
 	targetType := typ.Elem()
-	for {
+	if targetType.Kind() == reflect.Slice && !targetType.Implements(errorType) {
+		return asSlice(err, reflect.ValueOf(target).Elem(), walk)
+	}
+	found := false
+	walk(err, func(err error) bool {
+		if found {
+			return false
+		}
 		if reflect.TypeOf(err) == targetType {
 			reflect.ValueOf(target).Elem().Set(reflect.ValueOf(err))
-			return true
+			found = true
+		} else if x, ok := err.(interface{ As(interface{}) bool }); ok {
+			found = x.As(target)
 		}
-		if err = Unwrap(err); err == nil {
-			return false
+		return !found
+	})
+	return found
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// IsTypeName reports whether any error in err's chain has a dynamic type
+// whose name, as returned by the String method of reflect.Type, is typeName,
+// as in "*fs.PathError" or "net.UnknownNetworkError". It is meant for
+// classifying errors according to configuration, where types are only
+// known by name.
+//
+// Type names are fragile: they are qualified by package name rather than
+// import path, so distinct types may have the same name, and they change
+// when a type is renamed or moved, or changes from value to pointer
+// receivers. Prefer As when the type is available.
+func IsTypeName(err error, typeName string) bool {
+	found := false
+	Walk(err, func(err error) bool {
+		if !found && reflect.TypeOf(err).String() == typeName {
+			found = true
 		}
-	}
+		return !found
+	})
+	return found
+}
+
+// asSlice appends all errors in err's chain of the element type of the
+// slice s to s.
+func asSlice(err error, s reflect.Value, walk func(error, func(error) bool)) bool {
+	elemType := s.Type().Elem()
+	found := false
+	walk(err, func(err error) bool {
+		if reflect.TypeOf(err) == elemType {
+			s.Set(reflect.Append(s, reflect.ValueOf(err)))
+			found = true
+		}
+		return true
+	})
+	return found
 }
@@ -6,6 +6,7 @@ package errors_test
 
 import (
 	"os"
+	"reflect"
 	"testing"
 
 	"golang.org/x/exp/errors"
@@ -47,6 +48,366 @@ func TestIs(t *testing.T) {
 	}
 }
 
+func TestIsSentinelHierarchy(t *testing.T) {
+	errNotFound := errors.New("not found")
+	errUserNotFound := fmt.Errorf("user: %w", errNotFound)
+	testCases := []struct {
+		err, target error
+		want        bool
+	}{
+		{errUserNotFound, errNotFound, true},
+		{errUserNotFound, errUserNotFound, true},
+		{errNotFound, errUserNotFound, false},
+		{fmt.Errorf("get bob: %w", errUserNotFound), errNotFound, true},
+		{fmt.Errorf("get bob: %w", errUserNotFound), errUserNotFound, true},
+		{fmt.Errorf("get bob: %w", errNotFound), errUserNotFound, false},
+	}
+	for _, tc := range testCases {
+		if got := errors.Is(tc.err, tc.target); got != tc.want {
+			t.Errorf("Is(%v, %v) = %v, want %v", tc.err, tc.target, got, tc.want)
+		}
+	}
+}
+
+func TestIsNilTarget(t *testing.T) {
+	sentinels := map[string]error{"eof": os.ErrClosed}
+	// errorIs would panic if its Is method was called with nil.
+	err := fmt.Errorf("wrap: %v", errorIs("x"))
+	testCases := []struct {
+		err, target error
+		want        bool
+	}{
+		{nil, nil, true},
+		{nil, sentinels["missing"], true},
+		{err, nil, false},
+		{err, sentinels["missing"], false},
+		{multi{nil}, nil, false},
+	}
+	for _, tc := range testCases {
+		if got := errors.Is(tc.err, tc.target); got != tc.want {
+			t.Errorf("Is(%v, %v) = %v, want %v", tc.err, tc.target, got, tc.want)
+		}
+		if got := errors.IsAll(tc.err, tc.target); got != tc.want {
+			t.Errorf("IsAll(%v, %v) = %v, want %v", tc.err, tc.target, got, tc.want)
+		}
+		if got := errors.IsOneOf(tc.err, tc.target); got != tc.want {
+			t.Errorf("IsOneOf(%v, %v) = %v, want %v", tc.err, tc.target, got, tc.want)
+		}
+	}
+}
+
+// errorIs matches any error with the same text.
+type errorIs string
+
+func (e errorIs) Error() string { return string(e) }
+
+func (e errorIs) Is(target error) bool { return target.Error() == string(e) }
+
+func TestIsMethod(t *testing.T) {
+	err := fmt.Errorf("wrap: %v", errorIs("errorT"))
+	if !errors.Is(err, errorT{}) {
+		t.Errorf("Is(err, errorT{}) = false, want true")
+	}
+	if !errors.IsOneOf(err, errorD{}, errorT{}) {
+		t.Errorf("IsOneOf(err, errorD{}, errorT{}) = false, want true")
+	}
+	if errors.Is(err, errorD{}) {
+		t.Errorf("Is(err, errorD{}) = true, want false")
+	}
+}
+
+func TestIsPath(t *testing.T) {
+	err1 := errors.New("1")
+	err2 := errors.New("2")
+	tree := errors.Join(err2, fmt.Errorf("op: %v", errors.Join(errorT{}, err1)))
+	cycle := &cyclic{msg: "cycle"}
+	cycle.next = cycle
+	testCases := []struct {
+		err, target error
+		path        []int
+		ok          bool
+	}{
+		{nil, nil, []int{}, true},
+		{err1, nil, nil, false},
+		{fmt.Errorf("a: %v", fmt.Errorf("b: %v", err1)), err1, []int{}, true},
+		{tree, err1, []int{1, 1}, true},
+		{tree, err2, []int{0}, true},
+		{tree, errors.New("3"), nil, false},
+		{cycle, err1, nil, false},
+	}
+	for _, tc := range testCases {
+		path, ok := errors.IsPath(tc.err, tc.target)
+		if !reflect.DeepEqual(path, tc.path) || ok != tc.ok {
+			t.Errorf("IsPath(%v, %v) = %v, %v; want %v, %v", tc.err, tc.target, path, ok, tc.path, tc.ok)
+		}
+	}
+}
+
+func TestUnwrapTo(t *testing.T) {
+	isB := func(err error) bool { return err.Error() == "b: 1" }
+	err1 := errors.New("1")
+	b := fmt.Errorf("b: %v", err1)
+	cycle := &cyclic{msg: "cycle"}
+	cycle.next = cycle
+	testCases := []struct {
+		err  error
+		node error
+		ok   bool
+	}{
+		{nil, nil, false},
+		{err1, nil, false},
+		{b, b, true},
+		{fmt.Errorf("a: %v", b), b, true},
+		{errors.Join(err1, b), nil, false},
+		{fmt.Errorf("a: %v", errors.Join(err1, b)), nil, false},
+		{cycle, nil, false},
+	}
+	for _, tc := range testCases {
+		node, ok := errors.UnwrapTo(tc.err, isB)
+		if node != tc.node || ok != tc.ok {
+			t.Errorf("UnwrapTo(%v) = %v, %v; want %v, %v", tc.err, node, ok, tc.node, tc.ok)
+		}
+	}
+}
+
+func TestSwitch(t *testing.T) {
+	err1 := errors.New("1")
+	err2 := errors.New("2")
+	err3 := errors.New("3")
+	testCases := []struct {
+		err  error
+		want string
+	}{
+		{nil, "nil"},
+		{err3, ""},
+		{fmt.Errorf("a: %v", err2), "2"},
+		// The first case wins, even though err2 is found first in the chain.
+		{fmt.Errorf("a: %v", errors.Join(err2, err1)), "1"},
+		{errorIs("errorT"), "errorT"},
+	}
+	for _, tc := range testCases {
+		got := ""
+		ok := errors.Switch(tc.err,
+			errors.Case{Target: err1, Do: func() { got = "1" }},
+			errors.Case{Target: err2, Do: func() { got = "2" }},
+			errors.Case{Target: errorT{}, Do: func() { got = "errorT" }},
+			errors.Case{Target: nil, Do: func() { got = "nil" }},
+		)
+		if got != tc.want || ok != (tc.want != "") {
+			t.Errorf("Switch(%v): got case %q, %v; want %q", tc.err, got, ok, tc.want)
+		}
+	}
+}
+
+func TestIsNode(t *testing.T) {
+	err1 := errors.New("1")
+	match := errorIs("errorT")
+	err := fmt.Errorf("a: %v", fmt.Errorf("b: %v", match))
+	testCases := []struct {
+		err, target error
+		node        error
+		ok          bool
+	}{
+		{nil, nil, nil, true},
+		{err, nil, nil, false},
+		{err, errorT{}, match, true},
+		{err, match, match, true},
+		{fmt.Errorf("c: %v", err1), err1, err1, true},
+		{err, err1, nil, false},
+	}
+	for _, tc := range testCases {
+		node, ok := errors.IsNode(tc.err, tc.target)
+		if node != tc.node || ok != tc.ok {
+			t.Errorf("IsNode(%v, %v) = %v, %v; want %v, %v", tc.err, tc.target, node, ok, tc.node, tc.ok)
+		}
+	}
+}
+
+func TestSetSentinelMatchByValue(t *testing.T) {
+	defer errors.SetSentinelMatchByValue(false)
+
+	errX := errors.New("x")
+	fmtX := fmt.Errorf("x")
+	testCases := []struct {
+		err, target error
+		byValue     bool
+		want        bool
+	}{
+		{errors.New("x"), errX, false, false},
+		{fmt.Errorf("x"), fmtX, false, false},
+		{errX, errX, false, true},
+		{errors.New("x"), errX, true, true},
+		{fmt.Errorf("wrap: %v", errors.New("x")), errX, true, true},
+		{fmt.Errorf("x"), fmtX, true, true},
+		{errors.New("y"), errX, true, false},
+		{fmtX, errX, true, false},
+	}
+	for _, tc := range testCases {
+		errors.SetSentinelMatchByValue(tc.byValue)
+		if got := errors.Is(tc.err, tc.target); got != tc.want {
+			t.Errorf("byValue=%v: Is(%v, %v) = %v, want %v", tc.byValue, tc.err, tc.target, got, tc.want)
+		}
+	}
+}
+
+// listError is an error whose type is not comparable.
+type listError struct{ items []string }
+
+func (e listError) Error() string { return fmt.Sprint(e.items) }
+
+// listErrorIs is a non-comparable error that matches any listError.
+type listErrorIs struct{ listError }
+
+func (e listErrorIs) Is(target error) bool {
+	_, ok := target.(listError)
+	return ok
+}
+
+func TestIsNotComparable(t *testing.T) {
+	target := listError{[]string{"a"}}
+	err := fmt.Errorf("wrap: %v", listError{[]string{"a"}})
+	if errors.Is(err, target) {
+		t.Errorf("Is(err, target) = true, want false")
+	}
+	if errors.IsAll(err, target) || errors.IsOneOf(err, target) {
+		t.Errorf("IsAll or IsOneOf(err, target) = true, want false")
+	}
+	if ok, _ := errors.IsDebug(err, target); ok {
+		t.Errorf("IsDebug(err, target) = true, want false")
+	}
+	if !errors.Is(fmt.Errorf("wrap: %v", listErrorIs{}), target) {
+		t.Errorf("Is(listErrorIs, target) = false, want true")
+	}
+}
+
+// statusMatcher matches the errors with the HTTP status code it holds.
+type statusMatcher int
+
+func (m statusMatcher) Error() string { return fmt.Sprintf("status %d", int(m)) }
+
+func (m statusMatcher) MatchError(err error) bool {
+	return errors.Status(err) == int(m)
+}
+
+func TestIsTarget(t *testing.T) {
+	err := fmt.Errorf("get: %v", errors.WithStatus(errors.New("missing"), 404))
+	if !errors.Is(err, statusMatcher(404)) {
+		t.Errorf("Is(err, statusMatcher(404)) = false, want true")
+	}
+	if errors.Is(err, statusMatcher(403)) {
+		t.Errorf("Is(err, statusMatcher(403)) = true, want false")
+	}
+	if errors.Is(errors.New("plain"), statusMatcher(404)) {
+		t.Errorf("Is(plain, statusMatcher(404)) = true, want false")
+	}
+	if !errors.NewSet(statusMatcher(404)).MayContain(err) {
+		t.Errorf("NewSet(statusMatcher(404)).MayContain(err) = false, want true")
+	}
+}
+
+func TestIsAny(t *testing.T) {
+	testCases := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{errors.New("x"), true},
+		{fmt.Errorf("wrap: %v", errorT{}), true},
+		{errors.Opaque(errorT{}), true},
+	}
+	for _, tc := range testCases {
+		if got := errors.Is(tc.err, errors.Any); got != tc.want {
+			t.Errorf("Is(%v, Any) = %v, want %v", tc.err, got, tc.want)
+		}
+	}
+	if errors.Is(errors.Any, errorT{}) {
+		t.Errorf("Is(Any, errorT{}) = true, want false")
+	}
+}
+
+func TestSetOnMatch(t *testing.T) {
+	type call struct {
+		err, target error
+		matched     bool
+	}
+	var calls []call
+	errors.SetOnMatch(func(err, target error, matched bool) {
+		calls = append(calls, call{err, target, matched})
+	})
+	defer errors.SetOnMatch(nil)
+
+	err1 := errors.New("1")
+	err := fmt.Errorf("wrap: %v", err1)
+	errors.Is(err, err1)
+	errors.Is(err, os.ErrNotExist)
+	errors.IsAll(multi{err, err1}, err1)
+	errors.NewMatchCache(1).Is(err, os.ErrNotExist)
+	errors.NewAdaptiveMatcher(os.ErrNotExist, err1).Match(err)
+	// The Is method of WithCause matches outer without calling the hook.
+	caused := errors.WithCause(err, os.ErrClosed)
+	errors.Is(caused, err1)
+	want := []call{{err, err1, true}, {err, os.ErrNotExist, false}, {caused, err1, true}}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("hook calls: got %v; want %v", calls, want)
+	}
+}
+
+func TestIsAll(t *testing.T) {
+	err1 := errors.New("1")
+	err2 := errors.New("2")
+	both := multi{fmt.Errorf("a: %v", err1), err1}
+	some := multi{err1, err2}
+	testCases := []struct {
+		err, target error
+		want        bool
+	}{
+		{nil, nil, true},
+		{err1, nil, false},
+		{err1, err1, true},
+		{fmt.Errorf("wrap: %v", err1), err1, true},
+		{err2, err1, false},
+		{both, err1, true},
+		{fmt.Errorf("wrap: %v", both), err1, true},
+		{some, err1, false},
+		{some, err2, false},
+		{multi{}, err1, false},
+		{multi{errorIs("errorT"), errorT{}}, errorT{}, true},
+	}
+	for _, tc := range testCases {
+		if got := errors.IsAll(tc.err, tc.target); got != tc.want {
+			t.Errorf("IsAll(%v, %v) = %v, want %v", tc.err, tc.target, got, tc.want)
+		}
+	}
+}
+
+func TestIsOneOf(t *testing.T) {
+	err1 := errors.New("1")
+	err2 := errors.New("2")
+	err3 := errors.New("3")
+	erra := fmt.Errorf("wrap: %v", err1)
+
+	testCases := []struct {
+		err     error
+		targets []error
+		match   bool
+	}{
+		{nil, nil, false},
+		{nil, []error{err1, nil}, true},
+		{err1, nil, false},
+		{err1, []error{nil}, false},
+		{err1, []error{err1}, true},
+		{erra, []error{err2, err3, err1}, true},
+		{erra, []error{err2, err3}, false},
+		{multi{err2, erra}, []error{err3, err1}, true},
+		{errors.Opaque(err1), []error{err1}, false},
+	}
+	for _, tc := range testCases {
+		if got := errors.IsOneOf(tc.err, tc.targets...); got != tc.match {
+			t.Errorf("IsOneOf(%v, %v) = %v, want %v", tc.err, tc.targets, got, tc.match)
+		}
+	}
+}
+
 func TestAs(t *testing.T) {
 	var errT errorT
 	var errP *os.PathError
@@ -95,6 +456,80 @@ func TestAs(t *testing.T) {
 	}
 }
 
+func TestAsSlice(t *testing.T) {
+	_, errF := os.Open("non-existing")
+	_, errG := os.Open("non-existing-2")
+	err := multi{fmt.Errorf("open: %v", errF), errorT{}, errG}
+
+	var paths []*os.PathError
+	if !errors.As(err, &paths) {
+		t.Fatalf("As(err, &paths) = false, want true")
+	}
+	if len(paths) != 2 || paths[0] != errF || paths[1] != errG {
+		t.Errorf("paths = %v, want [%v %v]", paths, errF, errG)
+	}
+
+	// Matches are appended to the existing elements.
+	if !errors.As(errF, &paths) || len(paths) != 3 {
+		t.Errorf("As(errF, &paths): got %d paths, want 3", len(paths))
+	}
+
+	var errTs []errorT
+	if errors.As(errF, &errTs) || len(errTs) != 0 {
+		t.Errorf("As(errF, &errTs) = true, %v; want false, []", errTs)
+	}
+
+	// A slice type implementing error is matched as a single value.
+	var m multi
+	if !errors.As(fmt.Errorf("wrap: %v", err), &m) || len(m) != 3 {
+		t.Errorf("As(err, &m) = false, want true")
+	}
+}
+
+func TestIsWithin(t *testing.T) {
+	err1 := errors.New("1")
+	err := fmt.Errorf("a: %v", fmt.Errorf("b: %v", err1))
+	testCases := []struct {
+		err      error
+		target   error
+		maxDepth int
+		want     bool
+	}{
+		{err, err1, 3, true},
+		{err, err1, 2, false},
+		{err, err1, 0, false},
+		{err, err, 1, true},
+		{multi{errorT{}, err1}, err1, 3, true},
+		{multi{errorT{}, err1}, err1, 2, false},
+		{nil, nil, 0, true},
+	}
+	for _, tc := range testCases {
+		if got := errors.IsWithin(tc.err, tc.target, tc.maxDepth); got != tc.want {
+			t.Errorf("IsWithin(%v, %v, %d) = %v, want %v", tc.err, tc.target, tc.maxDepth, got, tc.want)
+		}
+	}
+
+	var c *cyclic
+	loop := &cyclic{msg: "loop"}
+	loop.next = fmt.Errorf("x: %v", loop)
+	if errors.AsWithin(fmt.Errorf("y: %v", errorT{}), &c, 10) {
+		t.Errorf("AsWithin(y, &c, 10) = true, want false")
+	}
+	if !errors.AsWithin(loop, &c, 1) || c != loop {
+		t.Errorf("AsWithin(loop, &c, 1) = false, want true")
+	}
+	var e errorT
+	if errors.AsWithin(err, &e, 10) {
+		t.Errorf("AsWithin(err, &errorT, 10) = true, want false")
+	}
+	if !errors.AsWithin(fmt.Errorf("z: %v", errorT{}), &e, 2) {
+		t.Errorf("AsWithin(z, &errorT, 2) = false, want true")
+	}
+	if errors.AsWithin(fmt.Errorf("z: %v", errorT{}), &e, 1) {
+		t.Errorf("AsWithin(z, &errorT, 1) = true, want false")
+	}
+}
+
 func TestUnwrap(t *testing.T) {
 	err1 := errors.New("1")
 	erra := fmt.Errorf("wrap 2: %v", err1)
@@ -120,6 +555,48 @@ func TestUnwrap(t *testing.T) {
 	}
 }
 
+// causer is an error of a package predating Unwrap, which exposes its cause
+// with a Cause method.
+type causer struct {
+	msg   string
+	cause error
+}
+
+func (e *causer) Error() string { return e.msg + ": " + e.cause.Error() }
+func (e *causer) Cause() error  { return e.cause }
+
+func init() {
+	errors.RegisterUnwrapper(func(err error) (error, bool) {
+		c, ok := err.(*causer)
+		if !ok {
+			return nil, false
+		}
+		return c.Cause(), true
+	})
+}
+
+func TestRegisterUnwrapper(t *testing.T) {
+	err1 := errors.New("1")
+	err := fmt.Errorf("wrap: %v", &causer{"legacy", err1})
+
+	if got := errors.Unwrap(errors.Unwrap(err)); got != err1 {
+		t.Errorf("Unwrap(Unwrap(err)) = %v, want %v", got, err1)
+	}
+	if !errors.Is(err, err1) {
+		t.Errorf("Is(err, err1) = false, want true")
+	}
+	var c *causer
+	if !errors.As(err, &c) {
+		t.Errorf("As(err, &c) = false, want true")
+	}
+	if got, want := errors.Causes(err), []string{"1", "legacy", "wrap"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Causes(err) = %q, want %q", got, want)
+	}
+	if errors.Is(&causer{"other", errorT{}}, err1) {
+		t.Errorf("Is(other, err1) = true, want false")
+	}
+}
+
 func TestOpaque(t *testing.T) {
 	got := fmt.Errorf("foo: %+v", errors.Opaque(errorT{}))
 	want := "foo: errorT"
@@ -134,6 +611,77 @@ func TestOpaque(t *testing.T) {
 	}
 }
 
+func TestIsTypeName(t *testing.T) {
+	err := fmt.Errorf("wrap: %v", &causer{"legacy", errorT{}})
+	testCases := []struct {
+		err      error
+		typeName string
+		want     bool
+	}{
+		{nil, "", false},
+		{err, "*fmt.withChain", true},
+		{err, "*errors_test.causer", true},
+		{err, "errors_test.causer", false},
+		{err, "errors_test.errorT", true},
+		{err, "*errors_test.errorT", false},
+		{err, "errorT", false},
+	}
+	for _, tc := range testCases {
+		if got := errors.IsTypeName(tc.err, tc.typeName); got != tc.want {
+			t.Errorf("IsTypeName(%v, %q) = %v, want %v", tc.err, tc.typeName, got, tc.want)
+		}
+	}
+}
+
+func TestAsInternal(t *testing.T) {
+	hidden := &causer{"hidden", errorT{}}
+	err := fmt.Errorf("public: %v", errors.Opaque(fmt.Errorf("inner: %v", hidden)))
+
+	var c *causer
+	if errors.As(err, &c) {
+		t.Errorf("As(err, &c) = true, want false")
+	}
+	if errors.Is(err, hidden) {
+		t.Errorf("Is(err, hidden) = true, want false")
+	}
+	if !errors.AsInternal(err, &c) || c != hidden {
+		t.Errorf("AsInternal(err, &c) = false, want true")
+	}
+	var e errorT
+	if !errors.AsInternal(err, &e) {
+		t.Errorf("AsInternal(err, &errorT) = false, want true")
+	}
+}
+
+func TestDeepAs(t *testing.T) {
+	hidden := &causer{"hidden", errorT{}}
+	suppressed := fmt.Errorf("close: %v", hidden)
+	cycle := &cyclic{msg: "cycle"}
+	cycle.next = cycle
+	testCases := []struct {
+		err  error
+		want *causer
+	}{
+		{nil, nil},
+		{cycle, nil},
+		{errors.Suppress(cycle, cycle), nil},
+		{hidden, hidden},
+		{fmt.Errorf("public: %v", errors.Opaque(hidden)), hidden},
+		{errors.Suppress(fmt.Errorf("write: %v", cycle), suppressed), hidden},
+		{errors.Opaque(errors.Join(cycle, errors.Suppress(cycle, errors.Opaque(suppressed)))), hidden},
+	}
+	for _, tc := range testCases {
+		got, ok := errors.DeepAs[*causer](tc.err)
+		if got != tc.want || ok != (tc.want != nil) {
+			t.Errorf("DeepAs(%v) = %v, %v; want %v", tc.err, got, ok, tc.want)
+		}
+	}
+	var c *causer
+	if errors.AsInternal(errors.Suppress(cycle, suppressed), &c) {
+		t.Errorf("AsInternal found a suppressed error")
+	}
+}
+
 type errorT struct{}
 
 func (errorT) Error() string { return "errorT" }
@@ -154,3 +702,17 @@ type wrapped struct{ error }
 func (wrapped) Error() string { return "wrapped" }
 
 func (wrapped) Unwrap() error { return nil }
+
+func BenchmarkIsMiss(b *testing.B) {
+	err := errors.New("leaf")
+	for i := 0; i < 20; i++ {
+		err = fmt.Errorf("wrap %d: %v", i, err)
+	}
+	target := errors.New("target")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if errors.Is(err, target) {
+			b.Fatal("Is: unexpected match")
+		}
+	}
+}